@@ -15,12 +15,22 @@ type AlertResponse struct {
 	Alert *Alert `json:"alert"`
 }
 
+type ListAlertsResponse struct {
+	Alerts    []*Alert `json:"alerts"`
+	NextToken string   `json:"nextToken,omitempty"`
+}
+
 type Alert struct {
 	Parameters  AlertParameters `json:"parameters"`
 	Id          string          `json:"id"`
 	Description string          `json:"description,omitempty"`
 	Enabled     bool            `json:"enabled"`
 	Channels    []AlertChannel  `json:"channels"`
+	Metadata
+
+	// ETag identifies the revision read from or written to the API. It is sent as
+	// If-Match on updates, so that changes made since then are not overwritten.
+	ETag string `json:"-"`
 }
 
 type AlertSnooze struct {
@@ -39,6 +49,28 @@ type AlertParameters struct {
 	Threshold AlertThreshold `json:"threshold"`
 	Frequency string         `json:"frequency"`
 	Window    string         `json:"window"`
+	// Extra are the parameters the fields above do not cover.
+	Extra ExtraParameters `json:"-"`
+}
+
+func (p AlertParameters) MarshalJSON() ([]byte, error) {
+	type fields AlertParameters
+	return marshalWithExtra(fields(p), p.Extra)
+}
+
+func (p *AlertParameters) UnmarshalJSON(data []byte) error {
+	type fields AlertParameters
+	var f fields
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	extra, err := unmarshalExtra(data, f)
+	if err != nil {
+		return err
+	}
+	*p = AlertParameters(f)
+	p.Extra = extra
+	return nil
 }
 
 type AlertThreshold struct {
@@ -47,13 +79,18 @@ type AlertThreshold struct {
 }
 
 func (c *Client) CreateAlert(ctx context.Context, alert *Alert) error {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
 	url := "/v1/alerts"
 	buf := new(bytes.Buffer)
-	_ = json.NewEncoder(buf).Encode(alert)
-	req, err := http.NewRequest(http.MethodPost, url, buf)
+	err := json.NewEncoder(buf).Encode(alert)
+	if err != nil {
+		return err
+	}
 	tflog.Trace(ctx, "creating an alert", map[string]interface{}{
-		"body": string(buf.Bytes()),
+		"body": redactBody(buf.Bytes()),
 	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, buf)
 	if err != nil {
 		return err
 	}
@@ -63,17 +100,23 @@ func (c *Client) CreateAlert(ctx context.Context, alert *Alert) error {
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to create alert with status %s", resp.Status)
+		return newAPIError(resp)
+	}
+	if err := decodeObject(resp, &AlertResponse{Alert: alert}); err != nil {
+		return err
 	}
+	alert.ETag = resp.Header.Get("ETag")
 	return nil
 }
 
 func (c *Client) GetAlert(ctx context.Context, alertId string) (*Alert, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
 	url := fmt.Sprintf("/v1/alerts/%s", alertId)
 	tflog.Trace(ctx, "getting an alert", map[string]interface{}{
 		"alertId": alertId,
 	})
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -89,22 +132,28 @@ func (c *Client) GetAlert(ctx context.Context, alertId string) (*Alert, error) {
 		return nil, nil
 	}
 	if resp.StatusCode != http.StatusOK {
+		apiErr := newAPIError(resp)
 		tflog.Error(ctx, "failed to get alert", map[string]interface{}{
 			"status":  resp.Status,
 			"alertId": alertId,
+			"error":   apiErr.Error(),
 		})
-		return nil, fmt.Errorf("failed to get alert with status %s", resp.Status)
+		return nil, apiErr
 	}
 	alertResponse := new(AlertResponse)
 	b, _ := io.ReadAll(resp.Body)
 	err = json.Unmarshal(b, alertResponse)
 	if alertResponse.Alert == nil {
 		tflog.Error(ctx, "failed to decode alert body", map[string]interface{}{"alertResponse": string(b)})
+	} else {
+		alertResponse.Alert.ETag = resp.Header.Get("ETag")
 	}
 	return alertResponse.Alert, err
 }
 
 func (c *Client) UpdateAlert(ctx context.Context, alert *Alert) error {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
 	url := fmt.Sprintf("/v1/alerts/%s", alert.Id)
 	buf := new(bytes.Buffer)
 	err := json.NewEncoder(buf).Encode(alert)
@@ -112,29 +161,36 @@ func (c *Client) UpdateAlert(ctx context.Context, alert *Alert) error {
 		return err
 	}
 	tflog.Trace(ctx, "updating an alert", map[string]interface{}{
-		"body": string(buf.Bytes()),
+		"body": redactBody(buf.Bytes()),
 	})
-	req, err := http.NewRequest(http.MethodPut, url, buf)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, buf)
 	if err != nil {
 		return err
 	}
+	setIfMatch(req, alert.ETag)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to update alert with status %s", resp.Status)
+		return newAPIError(resp)
+	}
+	if err := decodeObject(resp, &AlertResponse{Alert: alert}); err != nil {
+		return err
 	}
+	alert.ETag = resp.Header.Get("ETag")
 	return nil
 }
 
 func (c *Client) DeleteAlert(ctx context.Context, alertId string) error {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
 	url := fmt.Sprintf("/v1/alerts/%s", alertId)
 	tflog.Trace(ctx, "deleting an alert", map[string]interface{}{
 		"alertId": alertId,
 	})
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
 	}
@@ -144,7 +200,27 @@ func (c *Client) DeleteAlert(ctx context.Context, alertId string) error {
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to delete alert with status %s", resp.Status)
+		return newAPIError(resp)
 	}
 	return nil
 }
+
+// ListAlerts calls fn for every alert in the account, following pagination until fn
+// returns false or all pages have been read.
+func (c *Client) ListAlerts(ctx context.Context, opts *ListOptions, fn func(*Alert) bool) error {
+	return c.list(ctx, "/v1/alerts", opts, func(b []byte) (string, bool, error) {
+		page := new(ListAlertsResponse)
+		if err := json.Unmarshal(b, page); err != nil {
+			return "", false, err
+		}
+		for _, a := range page.Alerts {
+			if a == nil || !opts.matches(a.Id) {
+				continue
+			}
+			if !fn(a) {
+				return "", false, nil
+			}
+		}
+		return page.NextToken, true, nil
+	})
+}
@@ -1,9 +1,12 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -11,7 +14,39 @@ type Config struct {
 	APIKey    string
 	APIHost   string
 	ApiScheme string
-	Debug     bool
+	// Debug logs the requests and responses. Nil uses the BASELIME_DEBUG environment
+	// variable.
+	Debug *bool
+	// MaxRetries is the number of times a failed request is retried. Zero uses
+	// DefaultMaxRetries and a negative value disables retries.
+	MaxRetries int
+	// RetryMaxWait caps the wait between two attempts. Zero uses DefaultRetryMaxWait.
+	RetryMaxWait time.Duration
+	// RequestsPerSecond limits the request rate. Zero uses DefaultRequestsPerSecond.
+	RequestsPerSecond float64
+	// MaxConcurrentRequests limits the requests in flight. Zero uses DefaultMaxConcurrentRequests.
+	MaxConcurrentRequests int
+	// RequestTimeout bounds a single API call, including its retries. Zero uses
+	// DefaultRequestTimeout.
+	RequestTimeout time.Duration
+	// HTTPProxy is the URL of the proxy used to reach the API. When empty, the standard
+	// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables apply.
+	HTTPProxy string
+	// CACertFile and CACertPEM add a CA bundle to the system pool; only one can be set.
+	CACertFile string
+	CACertPEM  string
+	// InsecureSkipVerify disables the verification of the API's TLS certificate. Nil uses
+	// the BASELIME_INSECURE_SKIP_VERIFY environment variable.
+	InsecureSkipVerify *bool
+	// ClientCert* and ClientKey* configure a client certificate for mutual TLS, either
+	// as file paths or as PEM content.
+	ClientCertFile string
+	ClientKeyFile  string
+	ClientCertPEM  string
+	ClientKeyPEM   string
+	// DebugHARFile, when set together with Debug, is the path of a HAR file recording
+	// every request and response.
+	DebugHARFile string
 }
 
 type Client struct {
@@ -19,18 +54,58 @@ type Client struct {
 	httpClient *http.Client
 }
 
-func NewClient(config *Config) *Client {
+func NewClient(config *Config) (*Client, error) {
 	dCfg := defaultConfig()
 	dCfg.merge(config)
+	baseTransport, err := newTransport(dCfg)
+	if err != nil {
+		return nil, err
+	}
+	var transport http.RoundTripper = baseTransport
+	if dCfg.Debug != nil && *dCfg.Debug {
+		logging := &LoggingTransport{Transport: transport}
+		if dCfg.DebugHARFile != "" {
+			logging.HAR = NewHARWriter(dCfg.DebugHARFile, dCfg.Version)
+		}
+		transport = logging
+	}
 	httpClient := &http.Client{
-		Transport: &AddHeaderTransport{
-			Transport: http.DefaultTransport,
-			config:    dCfg,
+		Transport: &RetryTransport{
+			Transport: NewRateLimitTransport(
+				&AddHeaderTransport{
+					Transport: transport,
+					config:    dCfg,
+				},
+				dCfg.RequestsPerSecond,
+				dCfg.MaxConcurrentRequests,
+			),
+			MaxRetries: dCfg.MaxRetries,
+			MinWait:    minDuration(DefaultRetryMinWait, dCfg.RetryMaxWait),
+			MaxWait:    dCfg.RetryMaxWait,
 		},
 	}
 	return &Client{
-		config,
+		dCfg,
 		httpClient,
+	}, nil
+}
+
+// DefaultRequestTimeout is the time limit of a single API call, including its retries.
+const DefaultRequestTimeout = 2 * time.Minute
+
+// requestContext bounds ctx with the configured request timeout.
+func (c *Client) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.config == nil || c.config.RequestTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.config.RequestTimeout)
+}
+
+// setIfMatch makes req conditional on the object still being at revision etag. Objects
+// read before the API returned ETags have none, and are updated unconditionally.
+func setIfMatch(req *http.Request, etag string) {
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
 	}
 }
 
@@ -40,12 +115,30 @@ func defaultConfig() *Config {
 	if apiHost == "" {
 		apiHost = "go.baselime.io"
 	}
+	insecureSkipVerify, _ := strconv.ParseBool(os.Getenv("BASELIME_INSECURE_SKIP_VERIFY"))
+	debug, _ := strconv.ParseBool(os.Getenv("BASELIME_DEBUG"))
 	return &Config{
-		"0.1.1",
-		apiKey,
-		apiHost,
-		"https",
-		false,
+		Version:      "0.1.1",
+		APIKey:       apiKey,
+		APIHost:      apiHost,
+		ApiScheme:    "https",
+		Debug:        &debug,
+		MaxRetries:   DefaultMaxRetries,
+		RetryMaxWait: DefaultRetryMaxWait,
+
+		RequestsPerSecond:     DefaultRequestsPerSecond,
+		MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+		RequestTimeout:        DefaultRequestTimeout,
+
+		HTTPProxy:          os.Getenv("BASELIME_HTTP_PROXY"),
+		CACertFile:         os.Getenv("BASELIME_CA_CERT_FILE"),
+		CACertPEM:          os.Getenv("BASELIME_CA_CERT_PEM"),
+		InsecureSkipVerify: &insecureSkipVerify,
+		ClientCertFile:     os.Getenv("BASELIME_CLIENT_CERT_FILE"),
+		ClientKeyFile:      os.Getenv("BASELIME_CLIENT_KEY_FILE"),
+		ClientCertPEM:      os.Getenv("BASELIME_CLIENT_CERT_PEM"),
+		ClientKeyPEM:       os.Getenv("BASELIME_CLIENT_KEY_PEM"),
+		DebugHARFile:       os.Getenv("BASELIME_DEBUG_HAR_FILE"),
 	}
 }
 
@@ -59,7 +152,45 @@ func (cfg *Config) merge(cfg2 *Config) {
 	if cfg2.ApiScheme != "" {
 		cfg.ApiScheme = cfg2.ApiScheme
 	}
-	cfg.Debug = cfg2.Debug
+	if cfg2.Debug != nil {
+		cfg.Debug = cfg2.Debug
+	}
+	if cfg2.MaxRetries > 0 {
+		cfg.MaxRetries = cfg2.MaxRetries
+	} else if cfg2.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg2.RetryMaxWait > 0 {
+		cfg.RetryMaxWait = cfg2.RetryMaxWait
+	}
+	if cfg2.RequestsPerSecond > 0 {
+		cfg.RequestsPerSecond = cfg2.RequestsPerSecond
+	}
+	if cfg2.MaxConcurrentRequests > 0 {
+		cfg.MaxConcurrentRequests = cfg2.MaxConcurrentRequests
+	}
+	if cfg2.RequestTimeout > 0 {
+		cfg.RequestTimeout = cfg2.RequestTimeout
+	}
+	if cfg2.HTTPProxy != "" {
+		cfg.HTTPProxy = cfg2.HTTPProxy
+	}
+	// a CA bundle or client certificate from the configuration replaces the environment's
+	if cfg2.CACertFile != "" || cfg2.CACertPEM != "" {
+		cfg.CACertFile, cfg.CACertPEM = cfg2.CACertFile, cfg2.CACertPEM
+	}
+	if cfg2.InsecureSkipVerify != nil {
+		cfg.InsecureSkipVerify = cfg2.InsecureSkipVerify
+	}
+	if cfg2.ClientCertFile != "" || cfg2.ClientCertPEM != "" {
+		cfg.ClientCertFile, cfg.ClientCertPEM = cfg2.ClientCertFile, cfg2.ClientCertPEM
+	}
+	if cfg2.ClientKeyFile != "" || cfg2.ClientKeyPEM != "" {
+		cfg.ClientKeyFile, cfg.ClientKeyPEM = cfg2.ClientKeyFile, cfg2.ClientKeyPEM
+	}
+	if cfg2.DebugHARFile != "" {
+		cfg.DebugHARFile = cfg2.DebugHARFile
+	}
 }
 
 type AddHeaderTransport struct {
@@ -2,25 +2,42 @@ package client
 
 import (
 	"context"
-	"os"
+	"errors"
+	"github.com/baselime/terraform-provider-baselime/client/fakeapi"
+	"io"
+	"math/big"
+	"net/http"
+	"reflect"
 	"testing"
+	"time"
 )
 
-func TestClient_GetQueries(t *testing.T) {
-	config := &Config{
-		APIHost: "go.baselime.io",
-		APIKey:  os.Getenv("BASELIME_API_KEY"),
+const testAPIKey = "test-api-key"
+
+// newFakeClient returns a client talking to a fresh fakeapi server.
+func newFakeClient(t *testing.T, cfg Config) (*Client, *fakeapi.Server) {
+	t.Helper()
+	srv := fakeapi.NewServer(testAPIKey)
+	t.Cleanup(srv.Close)
+	cfg.APIHost = srv.Host()
+	cfg.ApiScheme = srv.Scheme()
+	cfg.APIKey = testAPIKey
+	if cfg.RetryMaxWait == 0 {
+		cfg.RetryMaxWait = 5 * time.Millisecond
 	}
-	c := NewClient(config)
-	q, err := c.GetQuery(context.Background(), "terraformed-query")
+	c, err := NewClient(&cfg)
 	if err != nil {
 		t.Fatal(err)
 	}
-	t.Log(q)
+	return c, srv
 }
 
-func TestClient_CreateQuery(t *testing.T) {
-	q := &Query{
+func filterValue(v QueryFilterValue) *QueryFilterValue {
+	return &v
+}
+
+func testQuery() *Query {
+	return &Query{
 		Id:          "terraformed-query",
 		Description: "Terraformed query",
 		Parameters: QueryParameters{
@@ -29,7 +46,7 @@ func TestClient_CreateQuery(t *testing.T) {
 				{
 					Key:       "message",
 					Operation: "INCLUDES",
-					Value:     "error",
+					Value:     filterValue(StringFilterValue("error")),
 					Type:      "string",
 				},
 			},
@@ -38,7 +55,6 @@ func TestClient_CreateQuery(t *testing.T) {
 				{
 					Key:      "count",
 					Operator: "COUNT",
-					//Alias:    "",
 				},
 			},
 			GroupBy: []QueryGroupBy{
@@ -59,16 +75,228 @@ func TestClient_CreateQuery(t *testing.T) {
 			},
 		},
 	}
-	config := &Config{
-		APIHost: "go.baselime.io",
-		//APIHost:   "localhost:32768",
-		//ApiScheme: "http",
-		APIKey: os.Getenv("BASELIME_API_KEY"),
+}
+
+func TestClient_CreateQuery(t *testing.T) {
+	c, srv := newFakeClient(t, Config{})
+	q := testQuery()
+	if err := c.CreateQuery(context.Background(), q); err != nil {
+		t.Fatal(err)
+	}
+	if q.WorkspaceId != srv.WorkspaceId || q.UserId != srv.UserId || q.Created == "" {
+		t.Errorf("CreateQuery() did not read the metadata set by the API: %+v", q.Metadata)
+	}
+	wantURL := "https://console.baselime.io/test-workspace/test-environment/queries/terraformed-query"
+	if q.ConsoleURL() != wantURL {
+		t.Errorf("ConsoleURL() = %q, want %q", q.ConsoleURL(), wantURL)
+	}
+	got, err := c.GetQuery(context.Background(), q.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, q) {
+		t.Errorf("GetQuery() = %+v, want %+v", got, q)
+	}
+
+	err = c.CreateQuery(context.Background(), q)
+	if !hasStatus(err, http.StatusConflict) {
+		t.Errorf("CreateQuery() of an existing query error = %v, want 409", err)
+	}
+
+	invalid := testQuery()
+	invalid.Id = "invalid-query"
+	invalid.Parameters.Datasets = nil
+	var apiErr *APIError
+	if err := c.CreateQuery(context.Background(), invalid); !errors.As(err, &apiErr) {
+		t.Fatalf("CreateQuery() of an invalid query error = %v, want *APIError", err)
+	}
+	if len(apiErr.Details) != 1 || apiErr.Details[0].Field != "parameters.datasets" {
+		t.Errorf("CreateQuery() error details = %+v, want parameters.datasets", apiErr.Details)
+	}
+	if _, ok := srv.Get(fakeapi.Queries, invalid.Id); ok {
+		t.Error("the invalid query was stored")
+	}
+}
+
+func TestClient_UpdateQuery(t *testing.T) {
+	c, srv := newFakeClient(t, Config{})
+	q := testQuery()
+	if err := c.UpdateQuery(context.Background(), q); !IsNotFound(err) {
+		t.Errorf("UpdateQuery() of a missing query error = %v, want 404", err)
+	}
+	if err := srv.Put(fakeapi.Queries, q); err != nil {
+		t.Fatal(err)
+	}
+	q.Description = "Updated"
+	if err := c.UpdateQuery(context.Background(), q); err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := srv.Get(fakeapi.Queries, q.Id)
+	if stored["description"] != "Updated" {
+		t.Errorf("stored description = %v, want Updated", stored["description"])
+	}
+}
+
+func TestClient_UpdateConflict(t *testing.T) {
+	c, srv := newFakeClient(t, Config{})
+	q := testQuery()
+	if err := c.CreateQuery(context.Background(), q); err != nil {
+		t.Fatal(err)
+	}
+	if q.ETag == "" || q.ETag != srv.ETag(fakeapi.Queries, q.Id) {
+		t.Fatalf("CreateQuery() ETag = %q, want %q", q.ETag, srv.ETag(fakeapi.Queries, q.Id))
+	}
+
+	// An edit in the console changes the ETag of the stored query
+	edited := testQuery()
+	edited.Description = "Edited in the console"
+	if err := srv.Put(fakeapi.Queries, edited); err != nil {
+		t.Fatal(err)
+	}
+	q.Description = "Updated"
+	if err := c.UpdateQuery(context.Background(), q); !IsPreconditionFailed(err) {
+		t.Fatalf("UpdateQuery() with a stale ETag error = %v, want 412", err)
+	}
+	if stored, _ := srv.Get(fakeapi.Queries, q.Id); stored["description"] != edited.Description {
+		t.Errorf("stored description = %v, want the console edit to be kept", stored["description"])
+	}
+
+	got, err := c.GetQuery(context.Background(), q.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.ETag = got.ETag
+	if err := c.UpdateQuery(context.Background(), q); err != nil {
+		t.Fatalf("UpdateQuery() after a refresh error = %v", err)
+	}
+	if q.ETag != srv.ETag(fakeapi.Queries, q.Id) {
+		t.Errorf("UpdateQuery() ETag = %q, want %q", q.ETag, srv.ETag(fakeapi.Queries, q.Id))
+	}
+}
+
+func TestClient_DeleteQuery(t *testing.T) {
+	c, srv := newFakeClient(t, Config{})
+	if err := srv.Put(fakeapi.Queries, testQuery()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteQuery(context.Background(), "terraformed-query"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := srv.Get(fakeapi.Queries, "terraformed-query"); ok {
+		t.Error("the query was not deleted")
+	}
+	if err := c.DeleteQuery(context.Background(), "terraformed-query"); !IsNotFound(err) {
+		t.Errorf("DeleteQuery() of a missing query error = %v, want 404", err)
+	}
+}
+
+func TestClient_Alerts(t *testing.T) {
+	c, _ := newFakeClient(t, Config{})
+	alert := &Alert{
+		Id:          "terraformed-alert",
+		Description: "Terraformed alert",
+		Enabled:     true,
+		Channels:    []AlertChannel{{Type: "email", Targets: []string{"foo@baselime.io"}}},
+		Parameters: AlertParameters{
+			QueryId:   "terraformed-query",
+			Threshold: AlertThreshold{Operation: ">", Value: big.NewFloat(10)},
+			Frequency: "5m",
+			Window:    "5m",
+		},
+	}
+	if err := c.CreateAlert(context.Background(), alert); !hasStatus(err, http.StatusBadRequest) {
+		t.Errorf("CreateAlert() referencing a missing query error = %v, want 400", err)
+	}
+	if err := c.CreateQuery(context.Background(), testQuery()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CreateAlert(context.Background(), alert); err != nil {
+		t.Fatal(err)
+	}
+	alert.Enabled = false
+	if err := c.UpdateAlert(context.Background(), alert); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.GetAlert(context.Background(), alert.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Enabled || got.Parameters.Threshold.Value.Cmp(big.NewFloat(10)) != 0 {
+		t.Errorf("GetAlert() = %+v", got)
+	}
+	if err := c.DeleteAlert(context.Background(), alert.Id); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c.GetAlert(context.Background(), alert.Id); got != nil || err != nil {
+		t.Errorf("GetAlert() after delete = %v, %v, want nil, nil", got, err)
 	}
-	c := NewClient(config)
-	err := c.CreateQuery(context.Background(), q)
+}
+
+func TestClient_Unauthorized(t *testing.T) {
+	_, srv := newFakeClient(t, Config{})
+	c, err := NewClient(&Config{APIHost: srv.Host(), ApiScheme: srv.Scheme(), APIKey: "wrong"})
 	if err != nil {
 		t.Fatal(err)
 	}
-	t.Log(q)
+	if _, err := c.GetQuery(context.Background(), "q"); !hasStatus(err, http.StatusUnauthorized) {
+		t.Errorf("GetQuery() with a wrong API key error = %v, want 401", err)
+	}
+}
+
+func TestClient_Faults(t *testing.T) {
+	c, srv := newFakeClient(t, Config{})
+	if err := srv.Put(fakeapi.Queries, testQuery()); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.InjectFault(fakeapi.Fault{Method: http.MethodGet, Status: http.StatusTooManyRequests, RetryAfter: "0", Times: 2})
+	if _, err := c.GetQuery(context.Background(), "terraformed-query"); err != nil {
+		t.Errorf("GetQuery() after two 429 responses error = %v", err)
+	}
+	if got := srv.Requests(); got != 3 {
+		t.Errorf("server received %d requests, want 3", got)
+	}
+
+	srv.InjectFault(fakeapi.Fault{Path: "/v1/queries", Status: http.StatusInternalServerError})
+	if err := c.CreateQuery(context.Background(), testQuery()); !hasStatus(err, http.StatusInternalServerError) {
+		t.Errorf("CreateQuery() error = %v, want 500", err)
+	}
+	srv.ClearFaults()
+
+	srv.InjectFault(fakeapi.Fault{Latency: time.Second})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetQuery(ctx, "terraformed-query"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetQuery() with latency error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestClient_RequestContext(t *testing.T) {
+	block := make(chan struct{})
+	t.Cleanup(func() { close(block) })
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		c := newTestClient(t, handler, Config{})
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+		_, err := c.GetAlert(ctx, "a")
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("GetAlert() error = %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("request timeout", func(t *testing.T) {
+		c := newTestClient(t, handler, Config{RequestTimeout: 20 * time.Millisecond})
+		err := c.UpdateDashboard(context.Background(), &Dashboard{Id: "d"})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("UpdateDashboard() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
 }
@@ -13,14 +13,46 @@ type GetDashboardResponse struct {
 	Dashboard *Dashboard `json:"dashboard"`
 }
 
+type ListDashboardsResponse struct {
+	Dashboards []*Dashboard `json:"dashboards"`
+	NextToken  string       `json:"nextToken,omitempty"`
+}
+
 type Dashboard struct {
 	Id          string              `json:"id"`
 	Description string              `json:"description,omitempty"`
 	Parameters  DashboardParameters `json:"parameters"`
+	Metadata
+
+	// ETag identifies the revision read from or written to the API. It is sent as
+	// If-Match on updates, so that changes made since then are not overwritten.
+	ETag string `json:"-"`
 }
 
 type DashboardParameters struct {
 	Widgets []DashboardWidget `json:"widgets"`
+	// Extra are the parameters the fields above do not cover.
+	Extra ExtraParameters `json:"-"`
+}
+
+func (p DashboardParameters) MarshalJSON() ([]byte, error) {
+	type fields DashboardParameters
+	return marshalWithExtra(fields(p), p.Extra)
+}
+
+func (p *DashboardParameters) UnmarshalJSON(data []byte) error {
+	type fields DashboardParameters
+	var f fields
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	extra, err := unmarshalExtra(data, f)
+	if err != nil {
+		return err
+	}
+	*p = DashboardParameters(f)
+	p.Extra = extra
+	return nil
 }
 
 type DashboardWidget struct {
@@ -41,13 +73,15 @@ var (
 
 // CreateDashboard creates a new dashboard
 func (c *Client) CreateDashboard(ctx context.Context, dashboard *Dashboard) error {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
 	path := "/v1/dashboards/"
 	buf := new(bytes.Buffer)
 	err := json.NewEncoder(buf).Encode(dashboard)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPost, path, buf)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, path, buf)
 	if err != nil {
 		return err
 	}
@@ -55,16 +89,23 @@ func (c *Client) CreateDashboard(ctx context.Context, dashboard *Dashboard) erro
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return newAPIError(resp)
 	}
+	if err := decodeObject(resp, &GetDashboardResponse{Dashboard: dashboard}); err != nil {
+		return err
+	}
+	dashboard.ETag = resp.Header.Get("ETag")
 	return nil
 }
 
 // GetDashboard retrieves an existing dashboard
 func (c *Client) GetDashboard(ctx context.Context, dashboardId string) (*Dashboard, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
 	path := fmt.Sprintf("/v1/dashboards/%s", dashboardId)
-	req, err := http.NewRequest(http.MethodGet, path, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -72,28 +113,36 @@ func (c *Client) GetDashboard(ctx context.Context, dashboardId string) (*Dashboa
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode == http.StatusNotFound {
 		tflog.Trace(ctx, "dashboard not found", map[string]interface{}{
 			"dashboardId": dashboardId,
 		})
 		return nil, nil
 	} else if resp.StatusCode != http.StatusOK {
+		apiErr := newAPIError(resp)
 		tflog.Error(ctx, "failed to get a dashboard", map[string]interface{}{
 			"status_code": resp.StatusCode,
 			"dashboardId": dashboardId,
+			"error":       apiErr.Error(),
 		})
-		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return nil, apiErr
 	}
 	var response GetDashboardResponse
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	if err != nil {
 		return nil, err
 	}
+	if response.Dashboard != nil {
+		response.Dashboard.ETag = resp.Header.Get("ETag")
+	}
 	return response.Dashboard, nil
 }
 
 // UpdateDashboard updates an existing dashboard
 func (c *Client) UpdateDashboard(ctx context.Context, dashboard *Dashboard) error {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
 	tflog.Trace(ctx, "updating dashboard", map[string]interface{}{
 		"name": dashboard.Id,
 	})
@@ -103,24 +152,32 @@ func (c *Client) UpdateDashboard(ctx context.Context, dashboard *Dashboard) erro
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPut, path, buf)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, path, buf)
 	if err != nil {
 		return err
 	}
+	setIfMatch(req, dashboard.ETag)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return newAPIError(resp)
 	}
+	if err := decodeObject(resp, &GetDashboardResponse{Dashboard: dashboard}); err != nil {
+		return err
+	}
+	dashboard.ETag = resp.Header.Get("ETag")
 	return nil
 }
 
 // DeleteDashboard deletes an existing dashboard
 func (c *Client) DeleteDashboard(ctx context.Context, dashboardId string) error {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
 	path := fmt.Sprintf("/v1/dashboards/%s", dashboardId)
-	req, err := http.NewRequest(http.MethodDelete, path, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
@@ -128,8 +185,29 @@ func (c *Client) DeleteDashboard(ctx context.Context, dashboardId string) error
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return newAPIError(resp)
 	}
 	return nil
 }
+
+// ListDashboards calls fn for every dashboard in the account, following pagination until
+// fn returns false or all pages have been read.
+func (c *Client) ListDashboards(ctx context.Context, opts *ListOptions, fn func(*Dashboard) bool) error {
+	return c.list(ctx, "/v1/dashboards", opts, func(b []byte) (string, bool, error) {
+		page := new(ListDashboardsResponse)
+		if err := json.Unmarshal(b, page); err != nil {
+			return "", false, err
+		}
+		for _, d := range page.Dashboards {
+			if d == nil || !opts.matches(d.Id) {
+				continue
+			}
+			if !fn(d) {
+				return "", false, nil
+			}
+		}
+		return page.NextToken, true, nil
+	})
+}
@@ -2,19 +2,69 @@ package client
 
 import (
 	"context"
-	"os"
+	"github.com/baselime/terraform-provider-baselime/client/fakeapi"
+	"net/http"
+	"reflect"
 	"testing"
 )
 
 func TestClient_GetDashboard(t *testing.T) {
-	config := &Config{
-		APIHost: "go.baselime.io",
-		APIKey:  os.Getenv("BASELIME_API_KEY"),
+	c, srv := newFakeClient(t, Config{})
+	if err := srv.Put(fakeapi.Queries, testQuery()); err != nil {
+		t.Fatal(err)
+	}
+	dashboard := &Dashboard{
+		Id:          "terraformed-dashboard",
+		Description: "Terraformed dashboard",
+		Parameters: DashboardParameters{
+			Widgets: []DashboardWidget{
+				{QueryId: "terraformed-query", Type: WidgetTypeTimeSeries, Name: "Errors"},
+			},
+		},
+	}
+	if err := c.CreateDashboard(context.Background(), dashboard); err != nil {
+		t.Fatal(err)
 	}
-	c := NewClient(config)
 	q, err := c.GetDashboard(context.Background(), "terraformed-dashboard")
 	if err != nil {
 		t.Fatal(err)
 	}
-	t.Log(q)
+	if !reflect.DeepEqual(q, dashboard) {
+		t.Errorf("GetDashboard() = %+v, want %+v", q, dashboard)
+	}
+
+	q, err = c.GetDashboard(context.Background(), "missing-dashboard")
+	if q != nil || err != nil {
+		t.Errorf("GetDashboard() of a missing dashboard = %v, %v, want nil, nil", q, err)
+	}
+}
+
+func TestClient_UpdateDashboard(t *testing.T) {
+	c, srv := newFakeClient(t, Config{})
+	if err := srv.Put(fakeapi.Queries, testQuery()); err != nil {
+		t.Fatal(err)
+	}
+	dashboard := &Dashboard{
+		Id: "terraformed-dashboard",
+		Parameters: DashboardParameters{
+			Widgets: []DashboardWidget{{QueryId: "terraformed-query", Type: WidgetTypeTable}},
+		},
+	}
+	if err := c.CreateDashboard(context.Background(), dashboard); err != nil {
+		t.Fatal(err)
+	}
+	dashboard.Parameters.Widgets[0].Type = "pie"
+	if err := c.UpdateDashboard(context.Background(), dashboard); !hasStatus(err, http.StatusBadRequest) {
+		t.Errorf("UpdateDashboard() with an invalid widget type error = %v, want 400", err)
+	}
+	dashboard.Parameters.Widgets[0].Type = WidgetTypeBar
+	if err := c.UpdateDashboard(context.Background(), dashboard); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteDashboard(context.Background(), dashboard.Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteDashboard(context.Background(), dashboard.Id); !IsNotFound(err) {
+		t.Errorf("DeleteDashboard() of a missing dashboard error = %v, want 404", err)
+	}
 }
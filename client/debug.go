@@ -0,0 +1,151 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const redacted = "***"
+
+// sensitiveHeaders are masked in debug logs and HAR files.
+var sensitiveHeaders = map[string]bool{
+	"x-api-key":     true,
+	"authorization": true,
+	"cookie":        true,
+	"set-cookie":    true,
+}
+
+// LoggingTransport records every request and response, with their headers, bodies and
+// timings, to tflog and optionally to a HAR file. API keys and alert channel targets
+// are masked.
+type LoggingTransport struct {
+	Transport http.RoundTripper
+	HAR       *HARWriter
+}
+
+func (lt *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = b
+		req.Body = io.NopCloser(bytes.NewReader(b))
+	}
+
+	tflog.Debug(ctx, "Baselime API request", map[string]interface{}{
+		"method":  req.Method,
+		"url":     req.URL.String(),
+		"headers": redactHeaders(req.Header),
+		"body":    redactBody(reqBody),
+	})
+
+	start := time.Now()
+	resp, err := lt.Transport.RoundTrip(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		tflog.Debug(ctx, "Baselime API request failed", map[string]interface{}{
+			"method":   req.Method,
+			"url":      req.URL.String(),
+			"duration": elapsed.String(),
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	tflog.Debug(ctx, "Baselime API response", map[string]interface{}{
+		"method":   req.Method,
+		"url":      req.URL.String(),
+		"status":   resp.Status,
+		"duration": elapsed.String(),
+		"headers":  redactHeaders(resp.Header),
+		"body":     redactBody(respBody),
+	})
+
+	if lt.HAR != nil {
+		if err := lt.HAR.Add(start, elapsed, req, reqBody, resp, respBody); err != nil {
+			tflog.Warn(ctx, "failed to write the HAR file", map[string]interface{}{"error": err.Error()})
+		}
+	}
+	return resp, nil
+}
+
+func redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		if sensitiveHeaders[strings.ToLower(k)] {
+			out[k] = redacted
+			continue
+		}
+		out[k] = strings.Join(v, ", ")
+	}
+	return out
+}
+
+// redactBody masks the targets of alert channels in a JSON body. Non-JSON bodies are
+// returned unchanged.
+func redactBody(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return string(b)
+	}
+	redactValue(v)
+	out, err := json.Marshal(v)
+	if err != nil {
+		return string(b)
+	}
+	return string(out)
+}
+
+func redactValue(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if k == "channels" {
+				redactChannels(child)
+				continue
+			}
+			redactValue(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			redactValue(child)
+		}
+	}
+}
+
+func redactChannels(v interface{}) {
+	channels, ok := v.([]interface{})
+	if !ok {
+		return
+	}
+	for _, c := range channels {
+		channel, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if targets, ok := channel["targets"].([]interface{}); ok {
+			for i := range targets {
+				targets[i] = redacted
+			}
+		}
+	}
+}
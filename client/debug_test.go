@@ -0,0 +1,104 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoggingTransport(t *testing.T) {
+	const secretTarget = "https://hooks.slack.com/services/T000/B000/secret"
+	harFile := filepath.Join(t.TempDir(), "baselime.har")
+	var gotBody string
+	debug := true
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(b)
+	}), Config{Debug: &debug, DebugHARFile: harFile})
+
+	var logs bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &logs)
+	alert := &Alert{
+		Id:       "errors",
+		Channels: []AlertChannel{{Type: "webhook", Targets: []string{secretTarget}}},
+	}
+	if err := c.CreateAlert(ctx, alert); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(gotBody, secretTarget) {
+		t.Errorf("the API did not receive the unmasked request body: %s", gotBody)
+	}
+	for name, content := range map[string]string{"logs": logs.String(), "HAR file": readFile(t, harFile)} {
+		if strings.Contains(content, secretTarget) {
+			t.Errorf("%s contain the channel target", name)
+		}
+		if strings.Contains(content, `"test"`) {
+			t.Errorf("%s contain the API key", name)
+		}
+		if !strings.Contains(content, "/v1/alerts") {
+			t.Errorf("%s do not record the request", name)
+		}
+	}
+
+	var har harLog
+	if err := json.Unmarshal([]byte(readFile(t, harFile)), &har); err != nil {
+		t.Fatalf("invalid HAR file: %s", err)
+	}
+	if len(har.Log.Entries) != 1 {
+		t.Fatalf("HAR file has %d entries, want 1", len(har.Log.Entries))
+	}
+	entry := har.Log.Entries[0]
+	if entry.Request.Method != http.MethodPost || entry.Response.Status != http.StatusCreated {
+		t.Errorf("unexpected HAR entry %s -> %d", entry.Request.Method, entry.Response.Status)
+	}
+	for _, h := range entry.Request.Headers {
+		if strings.EqualFold(h.Name, "x-api-key") && h.Value != redacted {
+			t.Errorf("x-api-key header is not masked: %q", h.Value)
+		}
+	}
+}
+
+func TestConfig_mergeDebug(t *testing.T) {
+	on, off := true, false
+	tests := []struct {
+		name  string
+		env   string
+		debug *bool
+		want  bool
+	}{
+		{name: "default", want: false},
+		{name: "environment", env: "1", want: true},
+		{name: "configuration", debug: &on, want: true},
+		{name: "configuration overriding the environment", env: "1", debug: &off, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BASELIME_DEBUG", tt.env)
+			cfg := defaultConfig()
+			cfg.merge(&Config{Debug: tt.debug})
+			if got := cfg.Debug != nil && *cfg.Debug; got != tt.want {
+				t.Errorf("Debug = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize caps how much of an error response body is read.
+const maxErrorBodySize = 64 << 10
+
+// requestIdHeaders lists the response headers that may carry the request ID, in order of preference.
+var requestIdHeaders = []string{"x-request-id", "x-amzn-requestid", "apigw-requestid"}
+
+// APIError is returned when the Baselime API responds with an unexpected status code.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Message    string
+	Details    []APIErrorDetail
+	RequestID  string
+	Method     string
+	Path       string
+}
+
+// APIErrorDetail describes a single problem reported by the API, usually a field validation error.
+type APIErrorDetail struct {
+	Field   string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s: %s", e.Method, e.Path, e.Status)
+	if e.Message != "" {
+		fmt.Fprintf(&sb, ": %s", e.Message)
+	}
+	for _, d := range e.Details {
+		if d.Field != "" {
+			fmt.Fprintf(&sb, "; %s: %s", d.Field, d.Message)
+		} else {
+			fmt.Fprintf(&sb, "; %s", d.Message)
+		}
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&sb, " (request id: %s)", e.RequestID)
+	}
+	return sb.String()
+}
+
+// IsNotFound reports whether err is an APIError with a 404 status code.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsPreconditionFailed reports whether err is an APIError with a 412 status code, which the
+// API returns when an update carries an If-Match header that no longer matches the object.
+func IsPreconditionFailed(err error) bool {
+	return hasStatus(err, http.StatusPreconditionFailed)
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// apiErrorBody covers the error payload shapes returned by the Baselime API.
+type apiErrorBody struct {
+	Message json.RawMessage   `json:"message"`
+	Error   json.RawMessage   `json:"error"`
+	Errors  []json.RawMessage `json:"errors"`
+	Details []json.RawMessage `json:"details"`
+}
+
+type apiErrorItem struct {
+	Field   string        `json:"field"`
+	Path    []interface{} `json:"path"`
+	Message string        `json:"message"`
+}
+
+// newAPIError builds an APIError from a failed response. It consumes the response body.
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+	if apiErr.Status == "" {
+		apiErr.Status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.Path = resp.Request.URL.Path
+	}
+	for _, h := range requestIdHeaders {
+		if id := resp.Header.Get(h); id != "" {
+			apiErr.RequestID = id
+			break
+		}
+	}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	apiErr.parseBody(b)
+	return apiErr
+}
+
+func (e *APIError) parseBody(b []byte) {
+	trimmed := strings.TrimSpace(string(b))
+	if trimmed == "" {
+		return
+	}
+	var body apiErrorBody
+	if err := json.Unmarshal(b, &body); err != nil {
+		e.Message = trimmed
+		return
+	}
+	e.Message = rawMessageString(body.Message)
+	if e.Message == "" {
+		e.Message = rawMessageString(body.Error)
+	}
+	for _, raw := range append(body.Errors, body.Details...) {
+		if d, ok := parseErrorDetail(raw); ok {
+			e.Details = append(e.Details, d)
+		}
+	}
+}
+
+// rawMessageString extracts a message from a JSON string or an object with a "message" field.
+func rawMessageString(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var item apiErrorItem
+	if err := json.Unmarshal(raw, &item); err == nil {
+		return item.Message
+	}
+	return ""
+}
+
+func parseErrorDetail(raw json.RawMessage) (APIErrorDetail, bool) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return APIErrorDetail{Message: s}, s != ""
+	}
+	var item apiErrorItem
+	if err := json.Unmarshal(raw, &item); err != nil || item.Message == "" {
+		return APIErrorDetail{}, false
+	}
+	field := item.Field
+	if field == "" && len(item.Path) > 0 {
+		parts := make([]string, 0, len(item.Path))
+		for _, p := range item.Path {
+			parts = append(parts, fmt.Sprint(p))
+		}
+		field = strings.Join(parts, ".")
+	}
+	return APIErrorDetail{Field: field, Message: item.Message}, true
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestClient_APIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		header      map[string]string
+		want        *APIError
+	}{
+		{
+			name:   "message with field details",
+			status: http.StatusBadRequest,
+			body:   `{"message":"Invalid query","errors":[{"path":["parameters","datasets"],"message":"Required"},{"field":"id","message":"Too long"}]}`,
+			header: map[string]string{"x-amzn-requestid": "req-1"},
+			want: &APIError{
+				StatusCode: http.StatusBadRequest,
+				Status:     "400 Bad Request",
+				Message:    "Invalid query",
+				Details: []APIErrorDetail{
+					{Field: "parameters.datasets", Message: "Required"},
+					{Field: "id", Message: "Too long"},
+				},
+				RequestID: "req-1",
+				Method:    http.MethodPost,
+				Path:      "/v1/queries",
+			},
+		},
+		{
+			name:   "error object",
+			status: http.StatusForbidden,
+			body:   `{"error":{"message":"Invalid API key"}}`,
+			want: &APIError{
+				StatusCode: http.StatusForbidden,
+				Status:     "403 Forbidden",
+				Message:    "Invalid API key",
+				Method:     http.MethodPost,
+				Path:       "/v1/queries",
+			},
+		},
+		{
+			name:        "plain text body",
+			status:      http.StatusBadGateway,
+			contentType: "text/plain",
+			body:        "upstream unavailable\n",
+			want: &APIError{
+				StatusCode: http.StatusBadGateway,
+				Status:     "502 Bad Gateway",
+				Message:    "upstream unavailable",
+				Method:     http.MethodPost,
+				Path:       "/v1/queries",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+			u, _ := url.Parse(srv.URL)
+			c, err := NewClient(&Config{APIHost: u.Host, ApiScheme: u.Scheme, APIKey: "test"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = c.CreateQuery(context.Background(), &Query{Id: "q"})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("CreateQuery() error = %v, want *APIError", err)
+			}
+			if !reflect.DeepEqual(apiErr, tt.want) {
+				t.Errorf("CreateQuery() error = %#v, want %#v", apiErr, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ExtraParameters are parameters of a query, an alert or a dashboard that the client has
+// no field for, as decoded from JSON. They are merged into the parameters sent to the
+// API, and hold the parameters received from the API that the fields did not read.
+type ExtraParameters map[string]interface{}
+
+// marshalWithExtra encodes fields, a parameters struct, with extra deep-merged into it.
+// Values of extra replace the ones of fields, except for objects, which are merged.
+func marshalWithExtra(fields interface{}, extra ExtraParameters) ([]byte, error) {
+	data, err := json.Marshal(fields)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	var params map[string]interface{}
+	if err := decodeJSON(data, &params); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergeParameters(params, extra))
+}
+
+// unmarshalExtra returns the parameters of data that fields, the parameters struct data
+// was decoded into, has no field for. It is nil when there are none.
+func unmarshalExtra(data []byte, fields interface{}) (ExtraParameters, error) {
+	var received map[string]interface{}
+	if err := decodeJSON(data, &received); err != nil {
+		return nil, err
+	}
+	extra := subtractParameters(reflect.TypeOf(fields), received)
+	if len(extra) == 0 {
+		return nil, nil
+	}
+	return extra, nil
+}
+
+func decodeJSON(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+// mergeParameters merges extra into params, recursively for objects.
+func mergeParameters(params, extra map[string]interface{}) map[string]interface{} {
+	for k, v := range extra {
+		existing, isObject := params[k].(map[string]interface{})
+		if extraObject, ok := v.(map[string]interface{}); ok && isObject {
+			params[k] = mergeParameters(existing, extraObject)
+			continue
+		}
+		params[k] = v
+	}
+	return params
+}
+
+// subtractParameters returns the values of received that t, a struct type, has no field
+// for, recursively for objects.
+func subtractParameters(t reflect.Type, received map[string]interface{}) map[string]interface{} {
+	fields := jsonFields(t)
+	result := map[string]interface{}{}
+	for k, v := range received {
+		fieldType, ok := fields[k]
+		if !ok {
+			result[k] = v
+			continue
+		}
+		if object, isObject := v.(map[string]interface{}); isObject && isJSONObject(fieldType) {
+			if rest := subtractParameters(fieldType, object); len(rest) > 0 {
+				result[k] = rest
+			}
+		}
+	}
+	return result
+}
+
+// OverlappingParameters returns the paths, such as needle.value, of the values of extra
+// that params, a parameters struct, has a field for. Objects of extra only overlap with
+// a struct field where their own values do.
+func OverlappingParameters(params interface{}, extra map[string]interface{}) []string {
+	var paths []string
+	overlappingParameters(reflect.TypeOf(params), extra, nil, &paths)
+	sort.Strings(paths)
+	return paths
+}
+
+func overlappingParameters(t reflect.Type, extra map[string]interface{}, prefix []string, paths *[]string) {
+	fields := jsonFields(t)
+	for k, v := range extra {
+		fieldType, ok := fields[k]
+		if !ok {
+			continue
+		}
+		path := append(append([]string{}, prefix...), k)
+		if object, isObject := v.(map[string]interface{}); isObject && isJSONObject(fieldType) {
+			overlappingParameters(fieldType, object, path, paths)
+			continue
+		}
+		*paths = append(*paths, strings.Join(path, "."))
+	}
+}
+
+// jsonFields returns the types of the fields of t, a struct type or a pointer to one, by
+// their JSON name. Pointers are dereferenced.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fieldType := t.Field(i).Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		fields[name] = fieldType
+	}
+	return fields
+}
+
+// isJSONObject reports whether t is a struct encoded as a JSON object of its fields.
+func isJSONObject(t reflect.Type) bool {
+	ptr := reflect.PointerTo(t)
+	return t.Kind() == reflect.Struct &&
+		!ptr.Implements(reflect.TypeOf((*json.Marshaler)(nil)).Elem()) &&
+		!ptr.Implements(reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem())
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestQueryParameters_ExtraJSON(t *testing.T) {
+	params := QueryParameters{
+		Datasets: []string{"lambda-logs"},
+		Needle:   &SearchNeedle{Value: "error"},
+		Extra: ExtraParameters{
+			"sampling": map[string]interface{}{"rate": json.Number("0.5")},
+			"needle":   map[string]interface{}{"fuzzy": true},
+		},
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	wantJSON := `{
+		"datasets": ["lambda-logs"],
+		"needle": {"value": "error", "fuzzy": true},
+		"sampling": {"rate": 0.5}
+	}`
+	if err := json.Unmarshal([]byte(wantJSON), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Marshal() = %s, want %s", data, wantJSON)
+	}
+
+	var decoded QueryParameters
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, params) {
+		t.Errorf("Unmarshal() = %+v, want %+v", decoded, params)
+	}
+}
+
+func TestQueryParameters_UnmarshalWithoutExtra(t *testing.T) {
+	var params QueryParameters
+	if err := json.Unmarshal([]byte(`{"datasets": ["lambda-logs"], "limit": 0, "filters": []}`), &params); err != nil {
+		t.Fatal(err)
+	}
+	if params.Extra != nil {
+		t.Errorf("Unmarshal() Extra = %v, want nil", params.Extra)
+	}
+}
+
+func TestOverlappingParameters(t *testing.T) {
+	tests := []struct {
+		name   string
+		params interface{}
+		extra  string
+		want   []string
+	}{
+		{
+			name:   "unknown parameters",
+			params: QueryParameters{},
+			extra:  `{"sampling": {"rate": 0.5}}`,
+		},
+		{
+			name:   "field",
+			params: QueryParameters{},
+			extra:  `{"limit": 10, "datasets": ["lambda-logs"]}`,
+			want:   []string{"datasets", "limit"},
+		},
+		{
+			name:   "nested unknown parameter",
+			params: QueryParameters{},
+			extra:  `{"needle": {"fuzzy": true}}`,
+		},
+		{
+			name:   "nested field",
+			params: QueryParameters{},
+			extra:  `{"needle": {"value": "error", "fuzzy": true}, "timeRange": {"to": "1h"}}`,
+			want:   []string{"needle.value", "timeRange.to"},
+		},
+		{
+			name:   "object replacing a field",
+			params: QueryParameters{},
+			extra:  `{"granularity": {"value": "1m"}}`,
+			want:   []string{"granularity"},
+		},
+		{
+			name:   "text field",
+			params: AlertParameters{},
+			extra:  `{"threshold": {"value": {"precision": 2}, "hysteresis": 1}}`,
+			want:   []string{"threshold.value"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var extra map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.extra), &extra); err != nil {
+				t.Fatal(err)
+			}
+			if got := OverlappingParameters(tt.params, extra); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OverlappingParameters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,436 @@
+// Package fakeapi provides an in-memory stand-in for the Baselime API, so the client and
+// the provider can be tested without network access or a real API key.
+//
+// The server implements the /v1/queries, /v1/alerts and /v1/dashboards endpoints with
+// the same payloads as the Baselime API, checks the x-api-key header, validates the
+// objects it receives and can inject latency and errors on demand. Every stored object
+// has an ETag, and updates carrying an If-Match header for another revision fail with
+// 412 Precondition Failed. Like the API, the server sets the workspaceId, environmentId,
+// userId, created and updated fields of the objects it stores.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind is the type of object stored by the server, named after its URL segment.
+type Kind string
+
+const (
+	Queries    Kind = "queries"
+	Alerts     Kind = "alerts"
+	Dashboards Kind = "dashboards"
+)
+
+// singular is the key wrapping a single object in request responses.
+var singular = map[Kind]string{
+	Queries:    "query",
+	Alerts:     "alert",
+	Dashboards: "dashboard",
+}
+
+// Object is a stored API object, as decoded from its JSON representation.
+type Object = map[string]interface{}
+
+// Fault describes an error or a delay injected into matching requests.
+type Fault struct {
+	// Method and Path restrict the fault to matching requests. Path is a prefix of the
+	// URL path. Empty values match every request.
+	Method string
+	Path   string
+	// Status is the status code returned instead of handling the request. Zero only
+	// applies the latency.
+	Status int
+	// RetryAfter is sent as the Retry-After header when set.
+	RetryAfter string
+	// Latency delays the response.
+	Latency time.Duration
+	// Times is the number of matching requests affected. Zero affects every matching request.
+	Times int
+}
+
+// Server is an in-memory Baselime API served over HTTP.
+type Server struct {
+	*httptest.Server
+	APIKey string
+	// WorkspaceId, EnvironmentId and UserId are set on the objects stored by the server.
+	WorkspaceId   string
+	EnvironmentId string
+	UserId        string
+
+	mu       sync.Mutex
+	objects  map[Kind]map[string]Object
+	etags    map[Kind]map[string]string
+	revision int
+	faults   []*Fault
+	requests int
+}
+
+// NewServer starts a server accepting apiKey. Close it when done.
+func NewServer(apiKey string) *Server {
+	s := &Server{
+		APIKey:        apiKey,
+		WorkspaceId:   "test-workspace",
+		EnvironmentId: "test-environment",
+		UserId:        "test-user",
+		objects: map[Kind]map[string]Object{
+			Queries:    {},
+			Alerts:     {},
+			Dashboards: {},
+		},
+		etags: map[Kind]map[string]string{
+			Queries:    {},
+			Alerts:     {},
+			Dashboards: {},
+		},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Host returns the host and port of the server, as used by the api_host provider attribute.
+func (s *Server) Host() string {
+	u, _ := url.Parse(s.URL)
+	return u.Host
+}
+
+// Scheme returns the scheme of the server, as used by the api_scheme provider attribute.
+func (s *Server) Scheme() string {
+	u, _ := url.Parse(s.URL)
+	return u.Scheme
+}
+
+// Put stores obj, replacing any object with the same id, for example to simulate an
+// edit in the Baselime console. obj can be any value that encodes to a JSON object
+// with an "id" field.
+func (s *Server) Put(kind Kind, obj interface{}) error {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	var o Object
+	if err := json.Unmarshal(b, &o); err != nil {
+		return err
+	}
+	id, _ := o["id"].(string)
+	if id == "" {
+		return fmt.Errorf("the object has no id")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(kind, id, o)
+	return nil
+}
+
+// ETag returns the ETag of a stored object.
+func (s *Server) ETag(kind Kind, id string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.etags[kind][id]
+}
+
+// Get returns a copy of a stored object.
+func (s *Server) Get(kind Kind, id string) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.objects[kind][id]
+	if !ok {
+		return nil, false
+	}
+	return copyObject(o), true
+}
+
+// Delete removes a stored object, for example to simulate a deletion outside Terraform.
+func (s *Server) Delete(kind Kind, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects[kind], id)
+	delete(s.etags[kind], id)
+}
+
+// InjectFault adds a fault. Faults are matched in the order they were added.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the number of requests received, including rejected ones.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	fault := s.matchFault(r)
+	s.mu.Unlock()
+
+	if fault != nil {
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.Status != 0 {
+			if fault.RetryAfter != "" {
+				w.Header().Set("Retry-After", fault.RetryAfter)
+			}
+			writeError(w, fault.Status, http.StatusText(fault.Status), nil)
+			return
+		}
+	}
+
+	if r.Header.Get("x-api-key") != s.APIKey {
+		writeError(w, http.StatusUnauthorized, "Invalid API key", nil)
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] != "v1" || len(segments) > 3 {
+		writeError(w, http.StatusNotFound, "Not found", nil)
+		return
+	}
+	kind := Kind(segments[1])
+	if _, ok := singular[kind]; !ok {
+		writeError(w, http.StatusNotFound, "Not found", nil)
+		return
+	}
+	id := ""
+	if len(segments) == 3 {
+		id = segments[2]
+	}
+
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		s.list(w, r, kind)
+	case id == "" && r.Method == http.MethodPost:
+		s.create(w, r, kind)
+	case id != "" && r.Method == http.MethodGet:
+		s.get(w, kind, id)
+	case id != "" && r.Method == http.MethodPut:
+		s.update(w, r, kind, id)
+	case id != "" && r.Method == http.MethodDelete:
+		s.delete(w, kind, id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed", nil)
+	}
+}
+
+// matchFault returns the first fault matching r and consumes one of its uses.
+// It must be called with the lock held.
+func (s *Server) matchFault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+		matched := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return &matched
+	}
+	return nil
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, kind Kind) {
+	q := r.URL.Query()
+	prefix := q.Get("namePrefix")
+	limit := 100
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "Invalid limit", nil)
+			return
+		}
+		limit = n
+	}
+	offset := 0
+	if v := q.Get("nextToken"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "Invalid nextToken", nil)
+			return
+		}
+		offset = n
+	}
+
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.objects[kind]))
+	for id := range s.objects[kind] {
+		if strings.HasPrefix(id, prefix) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	page := []Object{}
+	for i := offset; i < len(ids) && i < offset+limit; i++ {
+		page = append(page, copyObject(s.objects[kind][ids[i]]))
+	}
+	s.mu.Unlock()
+
+	body := map[string]interface{}{string(kind): page}
+	if offset+limit < len(ids) {
+		body["nextToken"] = strconv.Itoa(offset + limit)
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, kind Kind) {
+	obj, ok := decodeObject(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if errs := s.validate(kind, obj); len(errs) > 0 {
+		writeError(w, http.StatusBadRequest, "Validation failed", errs)
+		return
+	}
+	id := obj["id"].(string)
+	if _, exists := s.objects[kind][id]; exists {
+		writeError(w, http.StatusConflict, fmt.Sprintf("%s %s already exists", singular[kind], id), nil)
+		return
+	}
+	s.store(kind, id, obj)
+	w.Header().Set("ETag", s.etags[kind][id])
+	writeJSON(w, http.StatusCreated, map[string]interface{}{singular[kind]: obj})
+}
+
+func (s *Server) get(w http.ResponseWriter, kind Kind, id string) {
+	s.mu.Lock()
+	obj, ok := s.objects[kind][id]
+	if ok {
+		obj = copyObject(obj)
+	}
+	etag := s.etags[kind][id]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", singular[kind], id), nil)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	writeJSON(w, http.StatusOK, map[string]interface{}{singular[kind]: obj})
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request, kind Kind, id string) {
+	obj, ok := decodeObject(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.objects[kind][id]; !exists {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", singular[kind], id), nil)
+		return
+	}
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && ifMatch != "*" && ifMatch != s.etags[kind][id] {
+		writeError(w, http.StatusPreconditionFailed, fmt.Sprintf("%s %s has been modified", singular[kind], id), nil)
+		return
+	}
+	errs := s.validate(kind, obj)
+	if bodyId, _ := obj["id"].(string); bodyId != "" && bodyId != id {
+		errs = append(errs, fieldError{Path: []string{"id"}, Message: "does not match the id in the path"})
+	}
+	if len(errs) > 0 {
+		writeError(w, http.StatusBadRequest, "Validation failed", errs)
+		return
+	}
+	obj["id"] = id
+	s.store(kind, id, obj)
+	w.Header().Set("ETag", s.etags[kind][id])
+	writeJSON(w, http.StatusOK, map[string]interface{}{singular[kind]: obj})
+}
+
+func (s *Server) delete(w http.ResponseWriter, kind Kind, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.objects[kind][id]; !exists {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", singular[kind], id), nil)
+		return
+	}
+	delete(s.objects[kind], id)
+	delete(s.etags[kind], id)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+// store saves obj under a new revision, keeping the creation time and author of the object
+// it replaces. It must be called with the lock held.
+func (s *Server) store(kind Kind, id string, obj Object) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	prev := s.objects[kind][id]
+	for field, value := range map[string]string{"created": now, "userId": s.UserId} {
+		if prev != nil {
+			obj[field] = prev[field]
+		} else if _, ok := obj[field]; !ok {
+			obj[field] = value
+		}
+	}
+	obj["workspaceId"] = s.WorkspaceId
+	obj["environmentId"] = s.EnvironmentId
+	obj["updated"] = now
+	s.revision++
+	s.objects[kind][id] = obj
+	s.etags[kind][id] = fmt.Sprintf("\"%d\"", s.revision)
+}
+
+func decodeObject(w http.ResponseWriter, r *http.Request) (Object, bool) {
+	var obj Object
+	if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid JSON body: %s", err), nil)
+		return nil, false
+	}
+	return obj, true
+}
+
+type fieldError struct {
+	Path    []string `json:"path"`
+	Message string   `json:"message"`
+}
+
+func writeError(w http.ResponseWriter, status int, message string, errs []fieldError) {
+	body := map[string]interface{}{"message": message}
+	if len(errs) > 0 {
+		body["errors"] = errs
+	}
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func copyObject(o Object) Object {
+	b, _ := json.Marshal(o)
+	var c Object
+	_ = json.Unmarshal(b, &c)
+	return c
+}
@@ -0,0 +1,126 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func do(t *testing.T, s *Server, method, path, apiKey, body string) (*http.Response, map[string]interface{}) {
+	t.Helper()
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("x-api-key", apiKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var out map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return resp, out
+}
+
+func TestServer(t *testing.T) {
+	s := NewServer("key")
+	defer s.Close()
+
+	query := `{"id":"errors","parameters":{"datasets":["lambda-logs"]}}`
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		apiKey     string
+		body       string
+		wantStatus int
+	}{
+		{name: "wrong api key", method: http.MethodGet, path: "/v1/queries", apiKey: "wrong", wantStatus: http.StatusUnauthorized},
+		{name: "create query", method: http.MethodPost, path: "/v1/queries", body: query, wantStatus: http.StatusCreated},
+		{name: "create duplicate", method: http.MethodPost, path: "/v1/queries", body: query, wantStatus: http.StatusConflict},
+		{name: "create invalid", method: http.MethodPost, path: "/v1/queries", body: `{"id":"x","parameters":{}}`, wantStatus: http.StatusBadRequest},
+		{name: "alert on missing query", method: http.MethodPost, path: "/v1/alerts", body: `{"id":"a","parameters":{"queryId":"missing","frequency":"5m","window":"5m","threshold":{"operation":">"}}}`, wantStatus: http.StatusBadRequest},
+		{name: "alert", method: http.MethodPost, path: "/v1/alerts", body: `{"id":"a","parameters":{"queryId":"errors","frequency":"5m","window":"5m","threshold":{"operation":">"}}}`, wantStatus: http.StatusCreated},
+		{name: "dashboard with trailing slash", method: http.MethodPost, path: "/v1/dashboards/", body: `{"id":"d","parameters":{"widgets":[{"queryId":"errors","type":"table"}]}}`, wantStatus: http.StatusCreated},
+		{name: "get", method: http.MethodGet, path: "/v1/queries/errors", wantStatus: http.StatusOK},
+		{name: "get missing", method: http.MethodGet, path: "/v1/queries/missing", wantStatus: http.StatusNotFound},
+		{name: "update mismatched id", method: http.MethodPut, path: "/v1/queries/errors", body: `{"id":"other","parameters":{"datasets":["x"]}}`, wantStatus: http.StatusBadRequest},
+		{name: "delete", method: http.MethodDelete, path: "/v1/dashboards/d", wantStatus: http.StatusOK},
+		{name: "delete missing", method: http.MethodDelete, path: "/v1/dashboards/d", wantStatus: http.StatusNotFound},
+		{name: "unknown kind", method: http.MethodGet, path: "/v1/services", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiKey := tt.apiKey
+			if apiKey == "" {
+				apiKey = "key"
+			}
+			resp, body := do(t, s, tt.method, tt.path, apiKey, tt.body)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %v", resp.StatusCode, tt.wantStatus, body)
+			}
+		})
+	}
+}
+
+func TestServer_IfMatch(t *testing.T) {
+	s := NewServer("key")
+	defer s.Close()
+
+	resp, _ := do(t, s, http.MethodPost, "/v1/queries", "key", `{"id":"errors","parameters":{"datasets":["lambda-logs"]}}`)
+	etag := resp.Header.Get("ETag")
+	if etag == "" || etag != s.ETag(Queries, "errors") {
+		t.Fatalf("create ETag = %q, want %q", etag, s.ETag(Queries, "errors"))
+	}
+
+	tests := []struct {
+		name       string
+		ifMatch    string
+		wantStatus int
+	}{
+		{name: "stale revision", ifMatch: `"0"`, wantStatus: http.StatusPreconditionFailed},
+		{name: "current revision", ifMatch: etag, wantStatus: http.StatusOK},
+		{name: "previous revision", ifMatch: etag, wantStatus: http.StatusPreconditionFailed},
+		{name: "any revision", ifMatch: "*", wantStatus: http.StatusOK},
+		{name: "no If-Match", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPut, s.URL+"/v1/queries/errors", strings.NewReader(`{"id":"errors","parameters":{"datasets":["x"]}}`))
+			req.Header.Set("x-api-key", "key")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestServer_Faults(t *testing.T) {
+	s := NewServer("key")
+	defer s.Close()
+
+	s.InjectFault(Fault{Method: http.MethodGet, Path: "/v1/alerts", Status: http.StatusTooManyRequests, RetryAfter: "3", Times: 1})
+	if resp, _ := do(t, s, http.MethodGet, "/v1/queries", "key", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("unmatched path status = %d, want 200", resp.StatusCode)
+	}
+	resp, _ := do(t, s, http.MethodGet, "/v1/alerts", "key", "")
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "3" {
+		t.Errorf("faulted request status = %d, Retry-After = %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	if resp, _ := do(t, s, http.MethodGet, "/v1/alerts", "key", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("status after the fault was consumed = %d, want 200", resp.StatusCode)
+	}
+	if got := s.Requests(); got != 3 {
+		t.Errorf("Requests() = %d, want 3", got)
+	}
+}
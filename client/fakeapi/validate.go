@@ -0,0 +1,85 @@
+package fakeapi
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	idPattern       = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9-_.]{0,99}$`)
+	durationPattern = regexp.MustCompile(`^[0-9]+[smhd]$`)
+	widgetTypes     = map[string]bool{"timeseries": true, "statistic": true, "table": true, "timeseries-bar": true}
+	thresholdOps    = map[string]bool{">": true, ">=": true, "<": true, "<=": true, "=": true, "!=": true}
+)
+
+// validate checks obj the way the Baselime API does before storing it. It must be
+// called with the lock held, since alerts and dashboards must reference existing queries.
+func (s *Server) validate(kind Kind, obj Object) []fieldError {
+	var errs []fieldError
+	add := func(message string, path ...string) {
+		errs = append(errs, fieldError{Path: path, Message: message})
+	}
+
+	if id, _ := obj["id"].(string); !idPattern.MatchString(id) {
+		add("must be 1 to 100 letters, digits, dots, dashes or underscores", "id")
+	}
+	params, _ := obj["parameters"].(map[string]interface{})
+	if params == nil {
+		add("Required", "parameters")
+		return errs
+	}
+
+	switch kind {
+	case Queries:
+		if datasets, _ := params["datasets"].([]interface{}); len(datasets) == 0 {
+			add("must contain at least one dataset", "parameters", "datasets")
+		}
+		if granularity, ok := params["granularity"].(string); ok && !durationPattern.MatchString(granularity) {
+			add("must be a duration such as 5m", "parameters", "granularity")
+		}
+		if timeRange, ok := params["timeRange"].(map[string]interface{}); ok {
+			if from, _ := timeRange["from"].(string); !durationPattern.MatchString(from) {
+				add("must be a duration such as 5m", "parameters", "timeRange", "from")
+			}
+			if to, ok := timeRange["to"].(string); ok && !durationPattern.MatchString(to) {
+				add("must be a duration such as 5m", "parameters", "timeRange", "to")
+			}
+		}
+	case Alerts:
+		queryId, _ := params["queryId"].(string)
+		if queryId == "" {
+			add("Required", "parameters", "queryId")
+		} else if _, ok := s.objects[Queries][queryId]; !ok {
+			add(fmt.Sprintf("query %s not found", queryId), "parameters", "queryId")
+		}
+		for _, field := range []string{"frequency", "window"} {
+			if v, _ := params[field].(string); !durationPattern.MatchString(v) {
+				add("must be a duration such as 5m", "parameters", field)
+			}
+		}
+		threshold, _ := params["threshold"].(map[string]interface{})
+		if op, _ := threshold["operation"].(string); !thresholdOps[op] {
+			add("must be one of >, >=, <, <=, =, !=", "parameters", "threshold", "operation")
+		}
+		channels, _ := obj["channels"].([]interface{})
+		for i, c := range channels {
+			channel, _ := c.(map[string]interface{})
+			if t, _ := channel["type"].(string); t == "" {
+				add("Required", "channels", fmt.Sprint(i), "type")
+			}
+		}
+	case Dashboards:
+		widgets, _ := params["widgets"].([]interface{})
+		for i, w := range widgets {
+			widget, _ := w.(map[string]interface{})
+			queryId, _ := widget["queryId"].(string)
+			if _, ok := s.objects[Queries][queryId]; !ok {
+				add(fmt.Sprintf("query %q not found", queryId), "parameters", "widgets", fmt.Sprint(i), "queryId")
+			}
+			if t, _ := widget["type"].(string); !widgetTypes[t] {
+				add("must be one of timeseries, statistic, table, timeseries-bar", "parameters", "widgets", fmt.Sprint(i), "type")
+			}
+		}
+	}
+	return errs
+}
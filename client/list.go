@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ListOptions configures the List* methods.
+type ListOptions struct {
+	// NamePrefix only returns objects whose name starts with the prefix. The filter is
+	// applied by the API and enforced again on the returned pages.
+	NamePrefix string
+	// PageSize is the number of objects requested per page. Zero lets the API decide.
+	PageSize int
+}
+
+func (o *ListOptions) matches(id string) bool {
+	return o == nil || strings.HasPrefix(id, o.NamePrefix)
+}
+
+// pageFunc decodes a page body, calls the user callback for each object and returns
+// the token of the next page. It returns false when the callback stopped the iteration.
+type pageFunc func(body []byte) (nextToken string, more bool, err error)
+
+// list walks all pages of a list endpoint, following the nextToken of each page.
+func (c *Client) list(ctx context.Context, path string, opts *ListOptions, page pageFunc) error {
+	seen := map[string]bool{}
+	nextToken := ""
+	for {
+		params := url.Values{}
+		if opts != nil && opts.NamePrefix != "" {
+			params.Set("namePrefix", opts.NamePrefix)
+		}
+		if opts != nil && opts.PageSize > 0 {
+			params.Set("limit", strconv.Itoa(opts.PageSize))
+		}
+		if nextToken != "" {
+			params.Set("nextToken", nextToken)
+		}
+		u := path
+		if len(params) > 0 {
+			u = path + "?" + params.Encode()
+		}
+		tflog.Trace(ctx, "listing objects", map[string]interface{}{
+			"path":      path,
+			"nextToken": nextToken,
+		})
+		b, err := c.getPage(ctx, u)
+		if err != nil {
+			return err
+		}
+		token, more, err := page(b)
+		if err != nil {
+			return err
+		}
+		if !more || token == "" {
+			return nil
+		}
+		if seen[token] {
+			return fmt.Errorf("listing %s: the API returned the page token %q twice", path, token)
+		}
+		seen[token] = true
+		nextToken = token
+	}
+}
+
+// getPage sends a single list request, bounded by the configured request timeout.
+func (c *Client) getPage(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+	return io.ReadAll(resp.Body)
+}
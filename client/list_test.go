@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// pagedHandler serves ids in pages of two, filtered by the namePrefix query parameter.
+func pagedHandler(t *testing.T, key string, ids []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefix := r.URL.Query().Get("namePrefix")
+		var matching []map[string]string
+		for _, id := range ids {
+			if strings.HasPrefix(id, prefix) {
+				matching = append(matching, map[string]string{"id": id})
+			}
+		}
+		start := 0
+		if token := r.URL.Query().Get("nextToken"); token != "" {
+			if err := json.Unmarshal([]byte(token), &start); err != nil {
+				t.Errorf("invalid nextToken %q", token)
+			}
+		}
+		end := start + 2
+		body := map[string]interface{}{}
+		if end < len(matching) {
+			body["nextToken"] = string(mustJSON(end))
+		} else {
+			end = len(matching)
+		}
+		body[key] = matching[start:end]
+		_ = json.NewEncoder(w).Encode(body)
+	})
+}
+
+func mustJSON(v interface{}) []byte {
+	b, _ := json.Marshal(v)
+	return b
+}
+
+func TestClient_List(t *testing.T) {
+	ids := []string{"api-errors", "api-latency", "web-errors", "api-5xx", "worker-errors"}
+	tests := []struct {
+		name string
+		key  string
+		list func(c *Client, opts *ListOptions, fn func(id string) bool) error
+	}{
+		{
+			name: "queries",
+			key:  "queries",
+			list: func(c *Client, opts *ListOptions, fn func(id string) bool) error {
+				return c.ListQueries(context.Background(), opts, func(q *Query) bool { return fn(q.Id) })
+			},
+		},
+		{
+			name: "alerts",
+			key:  "alerts",
+			list: func(c *Client, opts *ListOptions, fn func(id string) bool) error {
+				return c.ListAlerts(context.Background(), opts, func(a *Alert) bool { return fn(a.Id) })
+			},
+		},
+		{
+			name: "dashboards",
+			key:  "dashboards",
+			list: func(c *Client, opts *ListOptions, fn func(id string) bool) error {
+				return c.ListDashboards(context.Background(), opts, func(d *Dashboard) bool { return fn(d.Id) })
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, pagedHandler(t, tt.key, ids), Config{})
+
+			var got []string
+			if err := tt.list(c, nil, func(id string) bool { got = append(got, id); return true }); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, ids) {
+				t.Errorf("all pages = %v, want %v", got, ids)
+			}
+
+			got = nil
+			if err := tt.list(c, &ListOptions{NamePrefix: "api-"}, func(id string) bool { got = append(got, id); return true }); err != nil {
+				t.Fatal(err)
+			}
+			if want := []string{"api-errors", "api-latency", "api-5xx"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("prefixed = %v, want %v", got, want)
+			}
+
+			got = nil
+			if err := tt.list(c, nil, func(id string) bool { got = append(got, id); return len(got) < 3 }); err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 3 {
+				t.Errorf("stopped iteration returned %d objects, want 3", len(got))
+			}
+		})
+	}
+}
+
+func TestClient_ListRepeatedToken(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"queries":[{"id":"q"}],"nextToken":"same"}`))
+	}), Config{})
+	err := c.ListQueries(context.Background(), nil, func(q *Query) bool { return true })
+	if err == nil {
+		t.Fatal("expected an error for a repeated page token")
+	}
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ConsoleURL is the address of the Baselime console.
+const ConsoleURL = "https://console.baselime.io"
+
+// Metadata holds the fields the API sets on every object. They are ignored when sent.
+type Metadata struct {
+	WorkspaceId   string `json:"workspaceId,omitempty"`
+	EnvironmentId string `json:"environmentId,omitempty"`
+	UserId        string `json:"userId,omitempty"`
+	Created       string `json:"created,omitempty"`
+	Updated       string `json:"updated,omitempty"`
+}
+
+// consoleURL returns the link to an object in the Baselime console, or an empty string if
+// the API did not say which workspace and environment the object belongs to.
+func (m *Metadata) consoleURL(kind, id string) string {
+	if m.WorkspaceId == "" || m.EnvironmentId == "" || id == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/%s/%s/%s", ConsoleURL,
+		url.PathEscape(m.WorkspaceId), url.PathEscape(m.EnvironmentId), kind, url.PathEscape(id))
+}
+
+// ConsoleURL returns the link to the query in the Baselime console.
+func (q *Query) ConsoleURL() string {
+	return q.consoleURL("queries", q.Id)
+}
+
+// ConsoleURL returns the link to the alert in the Baselime console.
+func (a *Alert) ConsoleURL() string {
+	return a.consoleURL("alerts", a.Id)
+}
+
+// ConsoleURL returns the link to the dashboard in the Baselime console.
+func (d *Dashboard) ConsoleURL() string {
+	return d.consoleURL("dashboards", d.Id)
+}
+
+// decodeObject updates the object wrapped in v with the response of a create or an
+// update, so that it reflects what the API stored. Empty bodies leave it unchanged.
+func decodeObject(resp *http.Response, v interface{}) error {
+	err := json.NewDecoder(resp.Body).Decode(v)
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
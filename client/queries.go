@@ -17,34 +17,108 @@ type CreateQueryResponse struct {
 	Query *Query `json:"query"`
 }
 
+type ListQueriesResponse struct {
+	Queries   []*Query `json:"queries"`
+	NextToken string   `json:"nextToken,omitempty"`
+}
+
 type Query struct {
 	Id          string          `json:"id"`
 	Description string          `json:"description"`
 	Parameters  QueryParameters `json:"parameters"`
+	Metadata
+
+	// ETag identifies the revision read from or written to the API. It is sent as
+	// If-Match on updates, so that changes made since then are not overwritten.
+	ETag string `json:"-"`
 }
 
 type QueryParameters struct {
 	Datasets          []string           `json:"datasets,omitempty"`
 	Filters           []QueryFilter      `json:"filters,omitempty"`
 	FilterCombination string             `json:"filterCombination,omitempty"`
+	FilterGroups      []QueryFilterGroup `json:"filterGroups,omitempty"`
 	Calculations      []QueryCalculation `json:"calculations,omitempty"`
 	GroupBy           []QueryGroupBy     `json:"groupBys,omitempty"`
 	OrderBy           *QueryOrderBy      `json:"orderBy,omitempty"`
 	Limit             int64              `json:"limit,omitempty"`
 	Needle            *SearchNeedle      `json:"needle,omitempty"`
+	TimeRange         *QueryTimeRange    `json:"timeRange,omitempty"`
+	Granularity       string             `json:"granularity,omitempty"`
+	Having            []QueryHaving      `json:"having,omitempty"`
+	// Extra are the parameters the fields above do not cover.
+	Extra ExtraParameters `json:"-"`
+}
+
+func (p QueryParameters) MarshalJSON() ([]byte, error) {
+	type fields QueryParameters
+	return marshalWithExtra(fields(p), p.Extra)
+}
+
+func (p *QueryParameters) UnmarshalJSON(data []byte) error {
+	type fields QueryParameters
+	var f fields
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	extra, err := unmarshalExtra(data, f)
+	if err != nil {
+		return err
+	}
+	*p = QueryParameters(f)
+	p.Extra = extra
+	return nil
+}
+
+// QueryTimeRange is the time range a query runs on by default, relative to when it runs.
+// From and To are durations such as 1h, To being empty for now.
+type QueryTimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to,omitempty"`
+}
+
+// QueryHaving filters the results of a query on the value of a calculation, named by its
+// alias or its operator.
+type QueryHaving struct {
+	Key       string  `json:"key"`
+	Operation string  `json:"operation"`
+	Value     float64 `json:"value"`
 }
 
 type QueryFilter struct {
-	Key       string `json:"key,omitempty"`
-	Operation string `json:"operation,omitempty"`
-	Value     string `json:"value,omitempty"`
-	Type      string `json:"type,omitempty"`
+	Key       string            `json:"key,omitempty"`
+	Operation string            `json:"operation,omitempty"`
+	Value     *QueryFilterValue `json:"value,omitempty"`
+	Type      string            `json:"type,omitempty"`
+}
+
+// Equal reports whether f and other are the same filter.
+func (f QueryFilter) Equal(other QueryFilter) bool {
+	if f.Key != other.Key || f.Operation != other.Operation || f.Type != other.Type {
+		return false
+	}
+	if f.Value == nil || other.Value == nil {
+		return f.Value == other.Value
+	}
+	return f.Value.Equal(*other.Value)
+}
+
+// QueryFilterGroup combines filters and nested groups with its own filter combination.
+// The groups of a query are combined with its filters using the filter combination of
+// the query.
+type QueryFilterGroup struct {
+	FilterCombination string             `json:"filterCombination,omitempty"`
+	Filters           []QueryFilter      `json:"filters,omitempty"`
+	FilterGroups      []QueryFilterGroup `json:"filterGroups,omitempty"`
 }
 
 type QueryCalculation struct {
 	Key      string `json:"key,omitempty"`
 	Operator string `json:"operator,omitempty"`
-	Alias    string `json:"alias,omitempty"`
+	// Parameter is the percentile of PERCENTILE calculations and the interval in seconds
+	// of RATE calculations.
+	Parameter *float64 `json:"parameter,omitempty"`
+	Alias     string   `json:"alias,omitempty"`
 }
 
 type QueryGroupBy struct {
@@ -64,6 +138,8 @@ type SearchNeedle struct {
 }
 
 func (c *Client) CreateQuery(ctx context.Context, query *Query) error {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
 	path := "/v1/queries"
 	buf := new(bytes.Buffer)
 	err := json.NewEncoder(buf).Encode(query)
@@ -73,18 +149,28 @@ func (c *Client) CreateQuery(ctx context.Context, query *Query) error {
 	tflog.Trace(ctx, "creating a query", map[string]interface{}{
 		"body": string(buf.Bytes()),
 	})
-	httpReq, err := http.NewRequest(http.MethodPost, path, buf)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, path, buf)
+	if err != nil {
+		return err
+	}
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("error creating query: %s", resp.Status)
+		return newAPIError(resp)
 	}
+	if err := decodeObject(resp, &CreateQueryResponse{Query: query}); err != nil {
+		return err
+	}
+	query.ETag = resp.Header.Get("ETag")
 	return nil
 }
 
 func (c *Client) GetQuery(ctx context.Context, queryId string) (*Query, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
 	if queryId == "" {
 		return nil, fmt.Errorf("queryId is required")
 	}
@@ -92,24 +178,38 @@ func (c *Client) GetQuery(ctx context.Context, queryId string) (*Query, error) {
 	tflog.Trace(ctx, "getting a query", map[string]interface{}{
 		"queryId": queryId,
 	})
-	httpReq, err := http.NewRequest("GET", url, nil)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode == http.StatusNotFound {
 		return nil, nil
 	} else if resp.StatusCode != http.StatusOK {
+		apiErr := newAPIError(resp)
 		tflog.Error(ctx, "error getting query", map[string]interface{}{
 			"queryId": queryId,
+			"error":   apiErr.Error(),
 		})
-		return nil, fmt.Errorf("error getting query: %s", resp.Status)
+		return nil, apiErr
 	}
 	response := new(GetQueryResponse)
-	return response.Query, json.NewDecoder(resp.Body).Decode(response)
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return nil, err
+	}
+	if response.Query != nil {
+		response.Query.ETag = resp.Header.Get("ETag")
+	}
+	return response.Query, nil
 }
 
 func (c *Client) UpdateQuery(ctx context.Context, query *Query) error {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
 	path := fmt.Sprintf("/v1/queries/%s", query.Id)
 	b, err := json.Marshal(query)
 	if err != nil {
@@ -123,23 +223,34 @@ func (c *Client) UpdateQuery(ctx context.Context, query *Query) error {
 	tflog.Trace(ctx, "updating a query", map[string]interface{}{
 		"body": string(b),
 	})
-	httpReq, err := http.NewRequest(http.MethodPut, path, buf)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, path, buf)
+	if err != nil {
+		return err
+	}
+	setIfMatch(httpReq, query.ETag)
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("error updating query: %s", resp.Status)
+		return newAPIError(resp)
 	}
+	if err := decodeObject(resp, &CreateQueryResponse{Query: query}); err != nil {
+		return err
+	}
+	query.ETag = resp.Header.Get("ETag")
 	return nil
 }
 
 func (c *Client) DeleteQuery(ctx context.Context, queryId string) error {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
 	path := fmt.Sprintf("/v1/queries/%s", queryId)
 	tflog.Trace(ctx, "deleting a query", map[string]interface{}{
 		"queryId": queryId,
 	})
-	httpReq, err := http.NewRequest(http.MethodDelete, path, nil)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
@@ -147,8 +258,29 @@ func (c *Client) DeleteQuery(ctx context.Context, queryId string) error {
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("error deleting query: %s", resp.Status)
+		return newAPIError(resp)
 	}
 	return nil
 }
+
+// ListQueries calls fn for every query in the account, following pagination until fn
+// returns false or all pages have been read.
+func (c *Client) ListQueries(ctx context.Context, opts *ListOptions, fn func(*Query) bool) error {
+	return c.list(ctx, "/v1/queries", opts, func(b []byte) (string, bool, error) {
+		page := new(ListQueriesResponse)
+		if err := json.Unmarshal(b, page); err != nil {
+			return "", false, err
+		}
+		for _, q := range page.Queries {
+			if q == nil || !opts.matches(q.Id) {
+				continue
+			}
+			if !fn(q) {
+				return "", false, nil
+			}
+		}
+		return page.NextToken, true, nil
+	})
+}
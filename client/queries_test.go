@@ -2,33 +2,64 @@ package client
 
 import (
 	"context"
+	"github.com/baselime/terraform-provider-baselime/client/fakeapi"
 	"net/http"
 	"reflect"
 	"testing"
 )
 
 func TestClient_GetQuery(t *testing.T) {
-	type fields struct {
-		config     *Config
-		httpClient *http.Client
-	}
 	type args struct {
 		queryId string
 	}
 	tests := []struct {
 		name    string
-		fields  fields
+		fault   *fakeapi.Fault
 		args    args
 		want    *Query
 		wantErr bool
 	}{
-		{},
+		{
+			name: "existing query",
+			args: args{queryId: "terraformed-query"},
+			want: testQuery(),
+		},
+		{
+			name: "missing query",
+			args: args{queryId: "missing-query"},
+			want: nil,
+		},
+		{
+			name:    "empty id",
+			args:    args{queryId: ""},
+			wantErr: true,
+		},
+		{
+			name:    "server error",
+			fault:   &fakeapi.Fault{Status: http.StatusInternalServerError},
+			args:    args{queryId: "terraformed-query"},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &Client{
-				config:     tt.fields.config,
-				httpClient: tt.fields.httpClient,
+			c, srv := newFakeClient(t, Config{MaxRetries: -1})
+			if err := srv.Put(fakeapi.Queries, testQuery()); err != nil {
+				t.Fatal(err)
+			}
+			if tt.fault != nil {
+				srv.InjectFault(*tt.fault)
+			}
+			if tt.want != nil {
+				stored, _ := srv.Get(fakeapi.Queries, tt.want.Id)
+				tt.want.Metadata = Metadata{
+					WorkspaceId:   srv.WorkspaceId,
+					EnvironmentId: srv.EnvironmentId,
+					UserId:        srv.UserId,
+					Created:       stored["created"].(string),
+					Updated:       stored["updated"].(string),
+				}
+				tt.want.ETag = srv.ETag(fakeapi.Queries, tt.want.Id)
 			}
 			got, err := c.GetQuery(context.Background(), tt.args.queryId)
 			if (err != nil) != tt.wantErr {
@@ -41,3 +72,25 @@ func TestClient_GetQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_ListQueries(t *testing.T) {
+	c, srv := newFakeClient(t, Config{})
+	for _, id := range []string{"api-errors", "api-latency", "web-errors"} {
+		q := testQuery()
+		q.Id = id
+		if err := srv.Put(fakeapi.Queries, q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var got []string
+	err := c.ListQueries(context.Background(), &ListOptions{NamePrefix: "api-", PageSize: 1}, func(q *Query) bool {
+		got = append(got, q.Id)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"api-errors", "api-latency"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListQueries() = %v, want %v", got, want)
+	}
+}
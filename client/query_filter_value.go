@@ -0,0 +1,166 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// FilterValueKind is the kind of value held by a QueryFilterValue.
+type FilterValueKind int
+
+const (
+	FilterValueString FilterValueKind = iota
+	FilterValueNumber
+	FilterValueBoolean
+	FilterValueList
+)
+
+// QueryFilterValue is the value a filter compares event fields with: a string, a number,
+// a boolean or, for IN and NOT_IN, a list of values. It is encoded in JSON as that value.
+// The zero value is the empty string.
+type QueryFilterValue struct {
+	kind FilterValueKind
+	// text is the string, or the number as written
+	text    string
+	boolean bool
+	list    []QueryFilterValue
+}
+
+func StringFilterValue(s string) QueryFilterValue {
+	return QueryFilterValue{kind: FilterValueString, text: s}
+}
+
+// NumberFilterValue returns the number n, which must be written as a JSON number.
+func NumberFilterValue(n string) (QueryFilterValue, error) {
+	if n == "" || (n[0] != '-' && (n[0] < '0' || n[0] > '9')) || strings.TrimSpace(n) != n || !json.Valid([]byte(n)) {
+		return QueryFilterValue{}, fmt.Errorf("invalid number %q", n)
+	}
+	return QueryFilterValue{kind: FilterValueNumber, text: n}, nil
+}
+
+func BoolFilterValue(b bool) QueryFilterValue {
+	return QueryFilterValue{kind: FilterValueBoolean, boolean: b}
+}
+
+func ListFilterValue(values ...QueryFilterValue) QueryFilterValue {
+	return QueryFilterValue{kind: FilterValueList, list: values}
+}
+
+func (v QueryFilterValue) Kind() FilterValueKind {
+	return v.kind
+}
+
+// String returns the string, the number as written or the boolean held by v. Lists are
+// written as their values separated by commas.
+func (v QueryFilterValue) String() string {
+	switch v.kind {
+	case FilterValueBoolean:
+		return strconv.FormatBool(v.boolean)
+	case FilterValueList:
+		values := make([]string, len(v.list))
+		for i, e := range v.list {
+			values[i] = e.String()
+		}
+		return strings.Join(values, ",")
+	}
+	return v.text
+}
+
+// List returns the values of a list, or nil if v is not a list.
+func (v QueryFilterValue) List() []QueryFilterValue {
+	return v.list
+}
+
+// NumbersEqual reports whether a and b, numbers written in JSON, have the same value,
+// however they are written, for example 0.5 and 0.50. Invalid numbers are compared as
+// text.
+func NumbersEqual(a, b string) bool {
+	x, _, errA := big.ParseFloat(a, 10, 256, big.ToNearestEven)
+	y, _, errB := big.ParseFloat(b, 10, 256, big.ToNearestEven)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return x.Cmp(y) == 0
+}
+
+// Equal reports whether v and other are the same value. Numbers are equal when they have
+// the same value, however they are written.
+func (v QueryFilterValue) Equal(other QueryFilterValue) bool {
+	if v.kind != other.kind {
+		return false
+	}
+	switch v.kind {
+	case FilterValueNumber:
+		return NumbersEqual(v.text, other.text)
+	case FilterValueBoolean:
+		return v.boolean == other.boolean
+	case FilterValueList:
+		if len(v.list) != len(other.list) {
+			return false
+		}
+		for i := range v.list {
+			if !v.list[i].Equal(other.list[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return v.text == other.text
+}
+
+func (v QueryFilterValue) MarshalJSON() ([]byte, error) {
+	switch v.kind {
+	case FilterValueNumber:
+		return json.Marshal(json.Number(v.text))
+	case FilterValueBoolean:
+		return json.Marshal(v.boolean)
+	case FilterValueList:
+		list := v.list
+		if list == nil {
+			list = []QueryFilterValue{}
+		}
+		return json.Marshal(list)
+	}
+	return json.Marshal(v.text)
+}
+
+func (v *QueryFilterValue) UnmarshalJSON(data []byte) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var raw interface{}
+	if err := d.Decode(&raw); err != nil {
+		return err
+	}
+	value, err := filterValueFromJSON(raw)
+	if err != nil {
+		return err
+	}
+	*v = value
+	return nil
+}
+
+func filterValueFromJSON(raw interface{}) (QueryFilterValue, error) {
+	switch raw := raw.(type) {
+	case string:
+		return StringFilterValue(raw), nil
+	case json.Number:
+		return NumberFilterValue(raw.String())
+	case bool:
+		return BoolFilterValue(raw), nil
+	case []interface{}:
+		values := make([]QueryFilterValue, len(raw))
+		for i, e := range raw {
+			value, err := filterValueFromJSON(e)
+			if err != nil {
+				return QueryFilterValue{}, err
+			}
+			values[i] = value
+		}
+		return ListFilterValue(values...), nil
+	}
+	return QueryFilterValue{}, fmt.Errorf("unexpected filter value %v", raw)
+}
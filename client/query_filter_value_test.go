@@ -0,0 +1,84 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestQueryFilterValue_JSON(t *testing.T) {
+	number := func(n string) QueryFilterValue {
+		v, err := NumberFilterValue(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	tests := []struct {
+		name  string
+		value QueryFilterValue
+		json  string
+	}{
+		{"string", StringFilterValue("error"), `"error"`},
+		{"numeric string", StringFilterValue("500"), `"500"`},
+		{"zero value", QueryFilterValue{}, `""`},
+		{"number", number("500"), `500`},
+		{"fraction", number("-0.25e3"), `-0.25e3`},
+		{"boolean", BoolFilterValue(true), `true`},
+		{"list", ListFilterValue(StringFilterValue("api"), StringFilterValue("a,b")), `["api","a,b"]`},
+		{"list of numbers", ListFilterValue(number("200"), number("204")), `[200,204]`},
+		{"empty list", ListFilterValue(), `[]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.json {
+				t.Errorf("Marshal() = %s, want %s", got, tt.json)
+			}
+			var decoded QueryFilterValue
+			if err := json.Unmarshal(got, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if !decoded.Equal(tt.value) || decoded.Kind() != tt.value.Kind() {
+				t.Errorf("Unmarshal() = %#v, want %#v", decoded, tt.value)
+			}
+		})
+	}
+}
+
+func TestQueryFilterValue_UnmarshalInvalid(t *testing.T) {
+	for _, data := range []string{`{"a":1}`, `[1,{}]`, `[`} {
+		var v QueryFilterValue
+		if err := json.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("Unmarshal(%s) = %#v, want an error", data, v)
+		}
+	}
+}
+
+func TestNumberFilterValue(t *testing.T) {
+	for n, valid := range map[string]bool{
+		"500": true, "-1.5": true, "1e10": true, "0": true,
+		"": false, "007": false, "1.": false, " 1": false, "+1": false, "0x10": false, "NaN": false, `"1"`: false,
+	} {
+		if _, err := NumberFilterValue(n); (err == nil) != valid {
+			t.Errorf("NumberFilterValue(%q) error = %v, want valid %v", n, err, valid)
+		}
+	}
+}
+
+func TestQueryFilterValue_Equal(t *testing.T) {
+	a, _ := NumberFilterValue("500")
+	b, _ := NumberFilterValue("500.0")
+	c, _ := NumberFilterValue("5e2")
+	if !a.Equal(b) || !a.Equal(c) {
+		t.Error("numbers with the same value should be equal")
+	}
+	if a.Equal(StringFilterValue("500")) {
+		t.Error("a number should not equal a string")
+	}
+	if ListFilterValue(a).Equal(ListFilterValue(a, a)) {
+		t.Error("lists of different lengths should not be equal")
+	}
+}
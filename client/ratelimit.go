@@ -0,0 +1,95 @@
+package client
+
+import (
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultRequestsPerSecond     = 10
+	DefaultMaxConcurrentRequests = 10
+)
+
+// RateLimitTransport limits both the rate and the number of in-flight requests sent
+// through it. A single instance is shared by every resource using the same Client,
+// so the limits apply across queries, alerts and dashboards.
+type RateLimitTransport struct {
+	Transport http.RoundTripper
+	limiter   *rate.Limiter
+	slots     chan struct{}
+}
+
+// NewRateLimitTransport returns a RateLimitTransport allowing requestsPerSecond requests
+// per second, with bursts of the same size, and at most maxConcurrent requests in flight.
+func NewRateLimitTransport(transport http.RoundTripper, requestsPerSecond float64, maxConcurrent int) *RateLimitTransport {
+	burst := int(math.Ceil(requestsPerSecond))
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimitTransport{
+		Transport: transport,
+		limiter:   rate.NewLimiter(rate.Limit(requestsPerSecond), burst),
+		slots:     make(chan struct{}, maxConcurrent),
+	}
+}
+
+func (rl *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	fields := map[string]interface{}{
+		"method": req.Method,
+		"path":   req.URL.Path,
+	}
+
+	select {
+	case rl.slots <- struct{}{}:
+	default:
+		tflog.Debug(ctx, "throttling request to the Baselime API: too many concurrent requests", fields)
+		select {
+		case rl.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release := func() { <-rl.slots }
+
+	reservation := rl.limiter.Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		fields["wait"] = delay.String()
+		tflog.Debug(ctx, "throttling request to the Baselime API: request rate exceeded", fields)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			reservation.Cancel()
+			release()
+			return nil, ctx.Err()
+		}
+	}
+
+	resp, err := rl.Transport.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	// keep the slot until the caller is done reading the response
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimitTransport_concurrency(t *testing.T) {
+	var inFlight, peak int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}), Config{RequestsPerSecond: 1000, MaxConcurrentRequests: 2})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.DeleteAlert(context.Background(), "a"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := atomic.LoadInt32(&peak); got > 2 {
+		t.Errorf("peak concurrent requests = %d, want at most 2", got)
+	}
+}
+
+func TestRateLimitTransport_rate(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), Config{RequestsPerSecond: 20})
+
+	start := time.Now()
+	// the first 20 requests use the burst, the next 5 are spread over 250ms
+	for i := 0; i < 25; i++ {
+		if err := c.DeleteDashboard(context.Background(), "d"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("25 requests at 20 rps took %s, want at least 200ms", elapsed)
+	}
+}
+
+func TestRateLimitTransport_contextCancelled(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), Config{RequestsPerSecond: 0.1})
+	if err := c.DeleteQuery(context.Background(), "q"); err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, "/v1/queries/q", nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.httpClient.Do(req.WithContext(ctx)); err == nil {
+		t.Fatal("expected the throttled request to be cancelled")
+	}
+}
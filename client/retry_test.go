@@ -0,0 +1,171 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.Handler, cfg Config) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return newTestClientFor(t, srv, cfg)
+}
+
+// newTestClientFor is newTestClient for a server already started, such as a TLS one.
+func newTestClientFor(t *testing.T, srv *httptest.Server, cfg Config) *Client {
+	t.Helper()
+	u, _ := url.Parse(srv.URL)
+	cfg.APIHost = u.Host
+	cfg.ApiScheme = u.Scheme
+	cfg.APIKey = "test"
+	c, err := NewClient(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		failStatus   int
+		maxRetries   int
+		call         func(c *Client) error
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{
+			name:         "retries GET on 502",
+			failures:     2,
+			failStatus:   http.StatusBadGateway,
+			call:         func(c *Client) error { _, err := c.GetQuery(context.Background(), "q"); return err },
+			wantAttempts: 3,
+		},
+		{
+			name:         "retries POST on 429",
+			failures:     1,
+			failStatus:   http.StatusTooManyRequests,
+			call:         func(c *Client) error { return c.CreateQuery(context.Background(), &Query{Id: "q"}) },
+			wantAttempts: 2,
+		},
+		{
+			name:         "does not retry POST on 500",
+			failures:     1,
+			failStatus:   http.StatusInternalServerError,
+			call:         func(c *Client) error { return c.CreateQuery(context.Background(), &Query{Id: "q"}) },
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "does not retry 400",
+			failures:     1,
+			failStatus:   http.StatusBadRequest,
+			call:         func(c *Client) error { return c.DeleteQuery(context.Background(), "q") },
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "gives up after max retries",
+			failures:     10,
+			failStatus:   http.StatusServiceUnavailable,
+			maxRetries:   2,
+			call:         func(c *Client) error { return c.DeleteQuery(context.Background(), "q") },
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "retries can be disabled",
+			failures:     1,
+			failStatus:   http.StatusTooManyRequests,
+			maxRetries:   -1,
+			call:         func(c *Client) error { return c.DeleteQuery(context.Background(), "q") },
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&attempts, 1)
+				if r.Body != nil {
+					b, _ := io.ReadAll(r.Body)
+					if r.Method == http.MethodPost && len(b) == 0 {
+						t.Errorf("attempt %d: empty request body", n)
+					}
+				}
+				if int(n) <= tt.failures {
+					w.WriteHeader(tt.failStatus)
+					return
+				}
+				if r.Method == http.MethodGet {
+					_, _ = w.Write([]byte(`{"query":{"id":"q"}}`))
+				}
+			}), Config{MaxRetries: tt.maxRetries, RetryMaxWait: 5 * time.Millisecond})
+
+			err := tt.call(c)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_backoff(t *testing.T) {
+	rt := &RetryTransport{MinWait: time.Second, MaxWait: 10 * time.Second}
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter string
+		min, max   time.Duration
+	}{
+		{name: "first attempt", attempt: 0, min: 500 * time.Millisecond, max: time.Second},
+		{name: "third attempt", attempt: 2, min: 2 * time.Second, max: 4 * time.Second},
+		{name: "capped", attempt: 10, min: 5 * time.Second, max: 10 * time.Second},
+		{name: "retry-after seconds", attempt: 0, retryAfter: "7", min: 7 * time.Second, max: 7 * time.Second},
+		{name: "retry-after capped", attempt: 0, retryAfter: "120", min: 10 * time.Second, max: 10 * time.Second},
+		{name: "retry-after date", attempt: 0, retryAfter: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), min: 0, max: 0},
+		{name: "retry-after invalid", attempt: 0, retryAfter: "soon", min: 500 * time.Millisecond, max: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			got := rt.backoff(tt.attempt, resp)
+			if got < tt.min || got > tt.max {
+				t.Errorf("backoff() = %s, want between %s and %s", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_contextCancelled(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}), Config{RetryMaxWait: time.Minute})
+	req, _ := http.NewRequest(http.MethodGet, "/v1/queries/q", nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.httpClient.Do(req.WithContext(ctx))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("retry did not stop on context cancellation, took %s", elapsed)
+	}
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// newTransport builds the base transport used to reach the Baselime API, applying the
+// proxy and TLS settings from cfg.
+func newTransport(cfg *Config) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.HTTPProxy != "" {
+		proxyURL, err := url.Parse(cfg.HTTPProxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid HTTP proxy URL %q", cfg.HTTPProxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+func newTLSConfig(cfg *Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify != nil && *cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CACertFile != "" && cfg.CACertPEM != "" {
+		return nil, errors.New("only one of the CA certificate file and the CA certificate PEM can be set")
+	}
+	caPEM := []byte(cfg.CACertPEM)
+	if cfg.CACertFile != "" {
+		b, err := os.ReadFile(cfg.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA certificate file: %w", err)
+		}
+		caPEM = b
+	}
+	if len(caPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("the CA certificate bundle does not contain any PEM encoded certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	certPEM, err := pemOrFile(cfg.ClientCertPEM, cfg.ClientCertFile, "client certificate")
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := pemOrFile(cfg.ClientKeyPEM, cfg.ClientKeyFile, "client key")
+	if err != nil {
+		return nil, err
+	}
+	if (len(certPEM) == 0) != (len(keyPEM) == 0) {
+		return nil, errors.New("a client certificate and a client key must be set together")
+	}
+	if len(certPEM) > 0 {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// pemOrFile returns the PEM content, reading it from file when given.
+func pemOrFile(pem, file, name string) ([]byte, error) {
+	if pem != "" && file != "" {
+		return nil, fmt.Errorf("only one of the %s file and the %s PEM can be set", name, name)
+	}
+	if file == "" {
+		return []byte(pem), nil
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s file: %w", name, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func tlsTestServer(t *testing.T, configure func(*tls.Config)) (*httptest.Server, string) {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	if configure != nil {
+		srv.TLS = &tls.Config{}
+		configure(srv.TLS)
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	return srv, string(caPEM)
+}
+
+func TestNewClient_TLS(t *testing.T) {
+	srv, caPEM := tlsTestServer(t, nil)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(caPEM), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	skipVerify, verify := true, false
+	tests := []struct {
+		name    string
+		env     string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "untrusted certificate", cfg: Config{}, wantErr: true},
+		{name: "CA PEM", cfg: Config{CACertPEM: caPEM}},
+		{name: "CA file", cfg: Config{CACertFile: caFile}},
+		{name: "insecure skip verify", cfg: Config{InsecureSkipVerify: &skipVerify}},
+		{name: "insecure skip verify from the environment", env: "true", cfg: Config{}},
+		{name: "verify overriding the environment", env: "true", cfg: Config{InsecureSkipVerify: &verify}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BASELIME_INSECURE_SKIP_VERIFY", tt.env)
+			// certificate errors fail the same way when retried
+			tt.cfg.MaxRetries = -1
+			err := newTestClientFor(t, srv, tt.cfg).DeleteQuery(context.Background(), "q")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewClient_mTLS(t *testing.T) {
+	certPEM, keyPEM := selfSignedCert(t)
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(certPEM)
+	srv, caPEM := tlsTestServer(t, func(c *tls.Config) {
+		c.ClientAuth = tls.RequireAndVerifyClientCert
+		c.ClientCAs = pool
+	})
+
+	if err := newTestClientFor(t, srv, Config{CACertPEM: caPEM, MaxRetries: -1}).DeleteQuery(context.Background(), "q"); err == nil {
+		t.Error("expected the request without a client certificate to fail")
+	}
+	c := newTestClientFor(t, srv, Config{CACertPEM: caPEM, ClientCertPEM: string(certPEM), ClientKeyPEM: string(keyPEM)})
+	if err := c.DeleteQuery(context.Background(), "q"); err != nil {
+		t.Errorf("DeleteQuery() with a client certificate error = %v", err)
+	}
+}
+
+func TestNewClient_Proxy(t *testing.T) {
+	var proxied int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&proxied, 1)
+		if r.URL.Host != "api.baselime.test" {
+			t.Errorf("proxy received request for %q", r.URL.Host)
+		}
+	}))
+	defer proxy.Close()
+
+	c, err := NewClient(&Config{APIHost: "api.baselime.test", ApiScheme: "http", HTTPProxy: proxy.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteQuery(context.Background(), "q"); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&proxied) != 1 {
+		t.Errorf("proxy received %d requests, want 1", proxied)
+	}
+}
+
+func TestNewClient_InvalidTLSConfig(t *testing.T) {
+	certPEM, _ := selfSignedCert(t)
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "both CA file and PEM", cfg: Config{CACertFile: "ca.pem", CACertPEM: "pem"}},
+		{name: "missing CA file", cfg: Config{CACertFile: filepath.Join(t.TempDir(), "missing.pem")}},
+		{name: "CA without certificates", cfg: Config{CACertPEM: "not a certificate"}},
+		{name: "certificate without key", cfg: Config{ClientCertPEM: string(certPEM)}},
+		{name: "invalid proxy", cfg: Config{HTTPProxy: "://proxy"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewClient(&tt.cfg); err == nil {
+				t.Error("NewClient() expected an error")
+			}
+		})
+	}
+}
+
+func selfSignedCert(t *testing.T) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
@@ -0,0 +1,7 @@
+data "baselime_alert" "errors" {
+  name = "lambda-errors-alert"
+}
+
+output "alert_channels" {
+  value = data.baselime_alert.errors.channels
+}
@@ -0,0 +1,14 @@
+# Every alert must notify the on-call Slack channel
+data "baselime_alerts" "all" {}
+
+data "baselime_alerts" "oncall" {
+  channel_type   = "slack"
+  channel_target = "oncall"
+}
+
+check "alerts_notify_oncall" {
+  assert {
+    condition     = length(setsubtract(data.baselime_alerts.all.names, data.baselime_alerts.oncall.names)) == 0
+    error_message = "Alerts not notifying the on-call Slack channel: ${join(", ", setsubtract(data.baselime_alerts.all.names, data.baselime_alerts.oncall.names))}"
+  }
+}
@@ -0,0 +1,7 @@
+data "baselime_dashboard" "overview" {
+  name = "platform-overview"
+}
+
+output "overview_queries" {
+  value = [for widget in data.baselime_dashboard.overview.widgets : widget.query_id]
+}
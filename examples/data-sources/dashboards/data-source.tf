@@ -0,0 +1,8 @@
+# Dashboards that would break if the query were deleted
+data "baselime_dashboards" "using_errors" {
+  query_id = "lambda-errors"
+}
+
+output "dashboards_using_errors" {
+  value = data.baselime_dashboards.using_errors.names
+}
@@ -0,0 +1,26 @@
+# Alert on every query of the API service
+data "baselime_queries" "api" {
+  name_regex = "^api-"
+  dataset    = "lambda-logs"
+}
+
+resource "baselime_alert" "api" {
+  for_each = toset(data.baselime_queries.api.names)
+
+  name        = "${each.key}-alert"
+  description = "Alert on ${each.key}"
+  enabled     = true
+  channels = [
+    {
+      type    = "slack"
+      targets = ["oncall"]
+    }
+  ]
+  query = each.key
+  threshold = {
+    operator = ">"
+    value    = 0
+  }
+  frequency = "5m"
+  window    = "5m"
+}
@@ -0,0 +1,23 @@
+# A query owned by the platform team, shared with every service team
+data "baselime_query" "shared" {
+  name = "lambda-errors"
+}
+
+resource "baselime_alert" "errors" {
+  name        = "checkout-errors"
+  description = "Errors in the checkout service"
+  enabled     = true
+  channels = [
+    {
+      type    = "email"
+      targets = ["checkout@example.com"]
+    }
+  ]
+  query = data.baselime_query.shared.name
+  threshold = {
+    operator = ">"
+    value    = 0
+  }
+  frequency = "5m"
+  window    = "5m"
+}
@@ -48,4 +48,7 @@ resource "baselime_dashboard" "terraformed" {
       description = "This is a line chart"
     }
   ]
-}
\ No newline at end of file
+}
+output "dashboard_url" {
+  value = baselime_dashboard.terraformed.url
+}
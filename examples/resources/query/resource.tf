@@ -8,6 +8,12 @@ resource "baselime_query" "terraformed" {
       operation = "INCLUDES"
       value     = "error"
       type      = "string"
+    },
+    {
+      key       = "@statusCode"
+      operation = "IN"
+      values    = [500, 502, 503]
+      type      = "number"
     }
   ]
   filter_combination = "AND"
@@ -34,4 +40,63 @@ resource "baselime_query" "terraformed" {
     is_regex   = true
     match_case = false
   }
-}
\ No newline at end of file
+}
+resource "baselime_query" "slow_errors" {
+  name        = "slow-errors"
+  description = "Errors of requests slower than 500ms"
+  datasets    = ["lambda-logs"]
+  where       = "message INCLUDES 'error' AND @duration > 500"
+  calculations = [
+    {
+      operator = "COUNT"
+    },
+    {
+      key       = "@duration"
+      operator  = "PERCENTILE"
+      parameter = 99.9
+      alias     = "p999_duration"
+    }
+  ]
+  time_range = {
+    from = "24h"
+  }
+  granularity = "15m"
+  having = [
+    {
+      key       = "COUNT"
+      operation = ">"
+      value     = 100
+    }
+  ]
+}
+resource "baselime_query" "service_errors" {
+  name               = "service-errors"
+  description        = "Errors of the api and worker services"
+  datasets           = ["lambda-logs"]
+  where              = "level = 'error'"
+  filter_combination = "AND"
+  filter_group {
+    filter_combination = "OR"
+    filters = [
+      {
+        key       = "service"
+        operation = "="
+        value     = "api"
+      },
+      {
+        key       = "service"
+        operation = "="
+        value     = "worker"
+      }
+    ]
+  }
+  calculations = [
+    {
+      operator = "COUNT"
+    }
+  ]
+  # parameters of the API not covered by the attributes above
+  parameters_json = jsonencode({
+    sampling = { rate = 0.1 }
+  })
+}
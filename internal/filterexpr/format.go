@@ -0,0 +1,107 @@
+package filterexpr
+
+import (
+	"github.com/baselime/terraform-provider-baselime/client"
+	"strings"
+)
+
+// Format returns the canonical expression of filters combined with combination, which
+// Parse turns back into the same filters. Keywords are upper case, conditions are
+// separated by single spaces and keys are only quoted when they have to be.
+func Format(filters []client.QueryFilter, combination string) string {
+	conditions := make([]string, len(filters))
+	for i, f := range filters {
+		conditions[i] = formatCondition(f)
+	}
+	if combination == "" {
+		combination = Or
+	}
+	return strings.Join(conditions, " "+strings.ToUpper(combination)+" ")
+}
+
+// Equivalent reports whether expr parses into filters combined with combination.
+func Equivalent(expr string, filters []client.QueryFilter, combination string) bool {
+	parsed, parsedCombination, err := Parse(expr)
+	if err != nil || len(parsed) != len(filters) {
+		return false
+	}
+	if len(filters) > 1 && !strings.EqualFold(parsedCombination, combination) {
+		return false
+	}
+	for i, f := range filters {
+		if f.Type == "" {
+			f.Type = TypeString
+		}
+		// the API leaves out empty values
+		if f.Value == nil && parsed[i].Value != nil && parsed[i].Value.Equal(client.StringFilterValue("")) {
+			parsed[i].Value = nil
+		}
+		if !parsed[i].Equal(f) {
+			return false
+		}
+	}
+	return true
+}
+
+func formatCondition(f client.QueryFilter) string {
+	condition := formatKey(f.Key) + " " + f.Operation
+	var value client.QueryFilterValue
+	if f.Value != nil {
+		value = *f.Value
+	}
+	switch f.Operation {
+	case "EXISTS", "DOES_NOT_EXIST":
+		return condition
+	case "IN", "NOT_IN":
+		values := value.List()
+		if value.Kind() != client.FilterValueList {
+			values = []client.QueryFilterValue{value}
+		}
+		formatted := make([]string, len(values))
+		for i, v := range values {
+			formatted[i] = formatValue(v, f.Type)
+		}
+		return condition + " (" + strings.Join(formatted, ", ") + ")"
+	}
+	return condition + " " + formatValue(value, f.Type)
+}
+
+// formatKey returns key as written in expressions.
+func formatKey(key string) string {
+	bare := key != "" && isWordStart(key[0]) && !isKeyword(key)
+	for i := 0; bare && i < len(key); i++ {
+		bare = isWordChar(key[i])
+	}
+	if bare {
+		return key
+	}
+	return quote(key, '"')
+}
+
+// formatValue returns value as written in expressions. Strings holding a value of the
+// type of the filter are written as that value, and the other strings are quoted.
+func formatValue(value client.QueryFilterValue, typ string) string {
+	text := value.String()
+	switch {
+	case value.Kind() == client.FilterValueNumber || value.Kind() == client.FilterValueBoolean:
+		return text
+	case typ == TypeNumber && isNumber(text):
+		return text
+	case typ == TypeBoolean && (text == "true" || text == "false"):
+		return text
+	}
+	return quote(text, '\'')
+}
+
+func quote(s string, q byte) string {
+	var b strings.Builder
+	b.WriteByte(q)
+	for i := 0; i < len(s); i++ {
+		if s[i] == q || s[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte(q)
+	return b.String()
+}
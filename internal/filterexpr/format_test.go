@@ -0,0 +1,133 @@
+package filterexpr
+
+import (
+	"github.com/baselime/terraform-provider-baselime/client"
+	"reflect"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		filters     []client.QueryFilter
+		combination string
+		want        string
+	}{
+		{
+			name: "and",
+			filters: []client.QueryFilter{
+				{Key: "message", Operation: "INCLUDES", Value: str("error"), Type: "string"},
+				{Key: "@duration", Operation: ">", Value: num("500"), Type: "number"},
+			},
+			combination: "AND",
+			want:        "message INCLUDES 'error' AND @duration > 500",
+		},
+		{
+			name: "default combination",
+			filters: []client.QueryFilter{
+				{Key: "cold_start", Operation: "=", Value: boolean(true), Type: "boolean"},
+				{Key: "error", Operation: "EXISTS"},
+			},
+			want: "cold_start = true OR error EXISTS",
+		},
+		{
+			name: "in",
+			filters: []client.QueryFilter{
+				{Key: "status", Operation: "IN", Value: list(*num("500"), *num("502")), Type: "number"},
+				{Key: "region", Operation: "NOT_IN", Value: list(*str("eu-west-1"), *str("us-east-1")), Type: "string"},
+			},
+			combination: "or",
+			want:        "status IN (500, 502) OR region NOT_IN ('eu-west-1', 'us-east-1')",
+		},
+		{
+			name: "values stored as strings",
+			filters: []client.QueryFilter{
+				{Key: "status", Operation: "=", Value: str("500"), Type: "number"},
+				{Key: "cold_start", Operation: "!=", Value: str("false"), Type: "boolean"},
+				{Key: "level", Operation: "=", Type: "string"},
+			},
+			combination: "AND",
+			want:        "status = 500 AND cold_start != false AND level = ''",
+		},
+		{
+			name: "quoting",
+			filters: []client.QueryFilter{
+				{Key: "user agent", Operation: "=", Value: str(`it's a \ test`), Type: "string"},
+				{Key: "or", Operation: "=", Value: str("500"), Type: "string"},
+				{Key: "9xx", Operation: "=", Value: str("not a number"), Type: "number"},
+			},
+			combination: "AND",
+			want:        `"user agent" = 'it\'s a \\ test' AND "or" = '500' AND "9xx" = 'not a number'`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(tt.filters, tt.combination); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat_RoundTrip(t *testing.T) {
+	for _, expr := range []string{
+		"message INCLUDES 'error' AND @duration > 500",
+		"level = 'error' or cold_start = TRUE or $baselime.service includes 'api'",
+		"a>=-1.5e3 AND b!='x'",
+		"status IN (500, 502,503) OR region NOT_IN ('eu-west-1')",
+		`"user agent" = 'it\'s a \\ test' AND "and" = 'x'`,
+		"error.stack EXISTS",
+		"region IN ('eu-west-1,us-east-1', 'a')",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			filters, combination, err := Parse(expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			canonical := Format(filters, combination)
+			got, gotCombination, err := Parse(canonical)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", canonical, err)
+			}
+			if !reflect.DeepEqual(got, filters) || (len(filters) > 1 && gotCombination != combination) {
+				t.Errorf("Parse(%q) = %+v %q, want %+v %q", canonical, got, gotCombination, filters, combination)
+			}
+			if !Equivalent(expr, filters, combination) {
+				t.Errorf("Equivalent(%q) = false", expr)
+			}
+			if again := Format(got, gotCombination); again != canonical {
+				t.Errorf("Format is not stable: %q, then %q", canonical, again)
+			}
+		})
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	filters := []client.QueryFilter{
+		{Key: "message", Operation: "INCLUDES", Value: str("error"), Type: "string"},
+		{Key: "@duration", Operation: ">", Value: num("500"), Type: "number"},
+	}
+	tests := []struct {
+		expr        string
+		filters     []client.QueryFilter
+		combination string
+		want        bool
+	}{
+		{"message includes 'error'  and @duration>500", filters, "AND", true},
+		{"message INCLUDES 'error' AND @duration > 500", filters, "OR", false},
+		{"message INCLUDES 'error' AND @duration > '500'", filters, "AND", false},
+		{"message INCLUDES 'error'", filters, "AND", false},
+		{"message INCLUDES 'error'", filters[:1], "OR", true},
+		{"message INCLUDES 'error' AND @duration > 500.0", filters, "AND", true},
+		{"error EXISTS", []client.QueryFilter{{Key: "error", Operation: "EXISTS"}}, "", true},
+		{"level = ''", []client.QueryFilter{{Key: "level", Operation: "=", Type: "string"}}, "", true},
+		{"message INCLUDES", filters[:1], "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if got := Equivalent(tt.expr, tt.filters, tt.combination); got != tt.want {
+				t.Errorf("Equivalent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
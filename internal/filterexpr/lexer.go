@@ -0,0 +1,192 @@
+package filterexpr
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	// tokenWord is a bare field key, a keyword operator, AND, OR, true or false.
+	tokenWord
+	// tokenQuotedKey is a field key in double quotes.
+	tokenQuotedKey
+	// tokenString is a value in single quotes.
+	tokenString
+	tokenNumber
+	// tokenSymbol is one of the operators =, !=, >, >=, < and <=.
+	tokenSymbol
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	// text is the unquoted text of the token.
+	text string
+	// offset is the byte offset of the token in the expression.
+	offset int
+}
+
+// describe returns the token as it is named in error messages.
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenQuotedKey:
+		return fmt.Sprintf("%q", t.text)
+	case tokenString:
+		return fmt.Sprintf("'%s'", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// lex splits expr into tokens, ending with a tokenEOF.
+func lex(expr string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for {
+		for i < len(expr) && isSpace(expr[i]) {
+			i++
+		}
+		if i == len(expr) {
+			// the end of an expression followed by newlines, such as a heredoc, is at the
+			// end of its last line
+			return append(tokens, token{kind: tokenEOF, offset: len(strings.TrimRight(expr, "\r\n"))}), nil
+		}
+		start := i
+		c := expr[i]
+		switch {
+		case c == '(':
+			tokens = append(tokens, token{tokenLParen, "(", start})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokenRParen, ")", start})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{tokenComma, ",", start})
+			i++
+		case c == '=':
+			tokens = append(tokens, token{tokenSymbol, "=", start})
+			i++
+		case c == '!' || c == '<' || c == '>':
+			op := string(c)
+			if i+1 < len(expr) && expr[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, newSyntaxError(expr, start, "unexpected \"!\", expected \"!=\"")
+			}
+			tokens = append(tokens, token{tokenSymbol, op, start})
+			i += len(op)
+		case c == '\'' || c == '"':
+			text, end, err := lexQuoted(expr, start)
+			if err != nil {
+				return nil, err
+			}
+			kind := tokenString
+			if c == '"' {
+				kind = tokenQuotedKey
+			}
+			tokens = append(tokens, token{kind, text, start})
+			i = end
+		case isDigit(c) || (c == '-' && i+1 < len(expr) && isDigit(expr[i+1])):
+			i++
+			for i < len(expr) && isWordChar(expr[i]) {
+				i++
+			}
+			if !isNumber(expr[start:i]) {
+				return nil, newSyntaxError(expr, start, fmt.Sprintf("invalid number %q", expr[start:i]))
+			}
+			tokens = append(tokens, token{tokenNumber, expr[start:i], start})
+		case isWordStart(c):
+			for i < len(expr) && isWordChar(expr[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenWord, expr[start:i], start})
+		default:
+			r, _ := utf8.DecodeRuneInString(expr[i:])
+			return nil, newSyntaxError(expr, start, fmt.Sprintf("unexpected character %q", r))
+		}
+	}
+}
+
+// lexQuoted reads the string quoted with expr[start] and returns its unescaped text and
+// the offset following the closing quote. A backslash escapes the next character.
+func lexQuoted(expr string, start int) (string, int, error) {
+	quote := expr[start]
+	var b strings.Builder
+	for i := start + 1; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			if i+1 == len(expr) {
+				return "", 0, newSyntaxError(expr, i, "unterminated escape sequence")
+			}
+			i++
+			b.WriteByte(expr[i])
+		case quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(expr[i])
+		}
+	}
+	return "", 0, newSyntaxError(expr, start, "unterminated quoted string")
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '@' || c == '$'
+}
+
+func isWordChar(c byte) bool {
+	return isWordStart(c) || isDigit(c) || c == '.' || c == '-' || c == '/' || c == ':'
+}
+
+// isNumber reports whether s is a decimal number as written in JSON, with an optional
+// minus sign, fraction and exponent, and no leading zeros.
+func isNumber(s string) bool {
+	i := 0
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+	digits := func() int {
+		n := 0
+		for i < len(s) && isDigit(s[i]) {
+			i++
+			n++
+		}
+		return n
+	}
+	start := i
+	if n := digits(); n == 0 || (n > 1 && s[start] == '0') {
+		return false
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		if digits() == 0 {
+			return false
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return i == len(s)
+}
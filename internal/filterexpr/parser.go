@@ -0,0 +1,226 @@
+// Package filterexpr implements the filter expressions of baselime_query, a compact
+// alternative to listing every filter as an object:
+//
+//	message INCLUDES 'error' AND @duration > 500
+//
+// An expression is one or more conditions joined by AND or by OR; the two cannot be
+// mixed, as a query has a single filter combination. A condition is a field key, an
+// operator and, except for EXISTS and DOES_NOT_EXIST, a value. IN and NOT_IN take a
+// parenthesised list of values.
+//
+// Keys are written bare, or in double quotes when they contain other characters or are a
+// keyword. Values are strings in single quotes, numbers, or true and false, which sets
+// the type of the filter. A backslash escapes the next character in quoted text.
+// Keywords are case insensitive.
+package filterexpr
+
+import (
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"strings"
+	"unicode/utf8"
+)
+
+// Filter combinations.
+const (
+	And = "AND"
+	Or  = "OR"
+)
+
+// Value types of the filters.
+const (
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+)
+
+// wordOperators are the filter operations written as words. The others are symbols.
+var wordOperators = []string{
+	"INCLUDES", "DOES_NOT_INCLUDE", "STARTS_WITH", "MATCH_REGEX",
+	"EXISTS", "DOES_NOT_EXIST", "IN", "NOT_IN",
+}
+
+// SyntaxError is an error in an expression, at a given position.
+type SyntaxError struct {
+	// Offset is the byte offset of the error in the expression.
+	Offset int
+	// Line is the 1-based line of the error, and Column its 1-based position in the line
+	// in characters.
+	Line    int
+	Column  int
+	Message string
+	// multiline is whether the expression has several lines, so that the message names
+	// the line.
+	multiline bool
+}
+
+func (e *SyntaxError) Error() string {
+	if e.multiline {
+		return fmt.Sprintf("%s at line %d, column %d", e.Message, e.Line, e.Column)
+	}
+	return fmt.Sprintf("%s at column %d", e.Message, e.Column)
+}
+
+func newSyntaxError(expr string, offset int, message string) *SyntaxError {
+	before := expr[:offset]
+	lineStart := strings.LastIndexByte(before, '\n') + 1
+	return &SyntaxError{
+		Offset:    offset,
+		Line:      strings.Count(before, "\n") + 1,
+		Column:    utf8.RuneCountInString(before[lineStart:]) + 1,
+		Message:   message,
+		multiline: strings.Contains(strings.TrimRight(expr, "\r\n"), "\n"),
+	}
+}
+
+// Parse returns the filters of expr and how they are combined. The combination is empty
+// when there is a single filter. Errors are *SyntaxError.
+func Parse(expr string) ([]client.QueryFilter, string, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, "", err
+	}
+	p := &parser{expr: expr, tokens: tokens}
+	var filters []client.QueryFilter
+	combination := ""
+	for {
+		filter, err := p.condition()
+		if err != nil {
+			return nil, "", err
+		}
+		filters = append(filters, filter)
+
+		t := p.next()
+		if t.kind == tokenEOF {
+			return filters, combination, nil
+		}
+		keyword := strings.ToUpper(t.text)
+		if t.kind != tokenWord || (keyword != And && keyword != Or) {
+			return nil, "", p.errorf(t, "expected AND, OR or end of expression, found %s", t.describe())
+		}
+		if combination != "" && keyword != combination {
+			return nil, "", p.errorf(t, "cannot combine AND and OR in the same expression")
+		}
+		combination = keyword
+	}
+}
+
+type parser struct {
+	expr   string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return newSyntaxError(p.expr, t.offset, fmt.Sprintf(format, args...))
+}
+
+// condition parses key operator [value].
+func (p *parser) condition() (client.QueryFilter, error) {
+	var filter client.QueryFilter
+	t := p.next()
+	switch {
+	case t.kind == tokenQuotedKey:
+		filter.Key = t.text
+	case t.kind == tokenWord && !isKeyword(t.text):
+		filter.Key = t.text
+	default:
+		return filter, p.errorf(t, "expected a field key, found %s", t.describe())
+	}
+
+	t = p.next()
+	switch {
+	case t.kind == tokenSymbol:
+		filter.Operation = t.text
+	case t.kind == tokenWord && contains(wordOperators, strings.ToUpper(t.text)):
+		filter.Operation = strings.ToUpper(t.text)
+	default:
+		return filter, p.errorf(t, "expected an operator after %q, found %s", filter.Key, t.describe())
+	}
+
+	switch filter.Operation {
+	case "EXISTS", "DOES_NOT_EXIST":
+		filter.Type = TypeString
+		return filter, nil
+	case "IN", "NOT_IN":
+		return filter, p.list(&filter)
+	}
+	t = p.next()
+	value, typ, ok := literal(t)
+	if !ok {
+		return filter, p.errorf(t, "expected a value after %s, found %s", filter.Operation, t.describe())
+	}
+	filter.Value, filter.Type = &value, typ
+	return filter, nil
+}
+
+// list parses the parenthesised values of an IN or NOT_IN filter.
+func (p *parser) list(filter *client.QueryFilter) error {
+	if t := p.next(); t.kind != tokenLParen {
+		return p.errorf(t, "expected \"(\" after %s, found %s", filter.Operation, t.describe())
+	}
+	var values []client.QueryFilterValue
+	for {
+		t := p.next()
+		value, typ, ok := literal(t)
+		if !ok {
+			return p.errorf(t, "expected a value, found %s", t.describe())
+		}
+		if filter.Type != "" && typ != filter.Type {
+			return p.errorf(t, "expected a %s value like the others in the list, found %s", filter.Type, t.describe())
+		}
+		filter.Type = typ
+		values = append(values, value)
+
+		t = p.next()
+		if t.kind == tokenRParen {
+			list := client.ListFilterValue(values...)
+			filter.Value = &list
+			return nil
+		}
+		if t.kind != tokenComma {
+			return p.errorf(t, "expected \",\" or \")\", found %s", t.describe())
+		}
+	}
+}
+
+// literal returns the value of t and its type, if t is a value.
+func literal(t token) (client.QueryFilterValue, string, bool) {
+	switch t.kind {
+	case tokenString:
+		return client.StringFilterValue(t.text), TypeString, true
+	case tokenNumber:
+		// the lexer only reads valid numbers
+		value, err := client.NumberFilterValue(t.text)
+		return value, TypeNumber, err == nil
+	case tokenWord:
+		switch strings.ToLower(t.text) {
+		case "true", "false":
+			return client.BoolFilterValue(strings.ToLower(t.text) == "true"), TypeBoolean, true
+		}
+	}
+	return client.QueryFilterValue{}, "", false
+}
+
+// isKeyword reports whether word cannot be used as a bare key.
+func isKeyword(word string) bool {
+	upper := strings.ToUpper(word)
+	return upper == And || upper == Or || upper == "TRUE" || upper == "FALSE" || contains(wordOperators, upper)
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
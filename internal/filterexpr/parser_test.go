@@ -0,0 +1,200 @@
+package filterexpr
+
+import (
+	"errors"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"reflect"
+	"testing"
+)
+
+func str(s string) *client.QueryFilterValue {
+	v := client.StringFilterValue(s)
+	return &v
+}
+
+func num(n string) *client.QueryFilterValue {
+	v, err := client.NumberFilterValue(n)
+	if err != nil {
+		panic(err)
+	}
+	return &v
+}
+
+func boolean(b bool) *client.QueryFilterValue {
+	v := client.BoolFilterValue(b)
+	return &v
+}
+
+func list(values ...client.QueryFilterValue) *client.QueryFilterValue {
+	v := client.ListFilterValue(values...)
+	return &v
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name            string
+		expr            string
+		wantFilters     []client.QueryFilter
+		wantCombination string
+	}{
+		{
+			name:        "string value",
+			expr:        "message INCLUDES 'error'",
+			wantFilters: []client.QueryFilter{{Key: "message", Operation: "INCLUDES", Value: str("error"), Type: "string"}},
+		},
+		{
+			name: "and",
+			expr: "message INCLUDES 'error' AND @duration > 500",
+			wantFilters: []client.QueryFilter{
+				{Key: "message", Operation: "INCLUDES", Value: str("error"), Type: "string"},
+				{Key: "@duration", Operation: ">", Value: num("500"), Type: "number"},
+			},
+			wantCombination: "AND",
+		},
+		{
+			name: "lower case keywords",
+			expr: "level = 'error' or cold_start = TRUE or $baselime.service includes 'api'",
+			wantFilters: []client.QueryFilter{
+				{Key: "level", Operation: "=", Value: str("error"), Type: "string"},
+				{Key: "cold_start", Operation: "=", Value: boolean(true), Type: "boolean"},
+				{Key: "$baselime.service", Operation: "INCLUDES", Value: str("api"), Type: "string"},
+			},
+			wantCombination: "OR",
+		},
+		{
+			name: "symbols without spaces",
+			expr: "a>=-1.5e3 AND b!='x' AND c<=0 AND d<1",
+			wantFilters: []client.QueryFilter{
+				{Key: "a", Operation: ">=", Value: num("-1.5e3"), Type: "number"},
+				{Key: "b", Operation: "!=", Value: str("x"), Type: "string"},
+				{Key: "c", Operation: "<=", Value: num("0"), Type: "number"},
+				{Key: "d", Operation: "<", Value: num("1"), Type: "number"},
+			},
+			wantCombination: "AND",
+		},
+		{
+			name: "exists",
+			expr: "error.stack EXISTS AND user DOES_NOT_EXIST",
+			wantFilters: []client.QueryFilter{
+				{Key: "error.stack", Operation: "EXISTS", Type: "string"},
+				{Key: "user", Operation: "DOES_NOT_EXIST", Type: "string"},
+			},
+			wantCombination: "AND",
+		},
+		{
+			name: "in",
+			expr: "status IN (500, 502,503) OR region NOT_IN ('eu-west-1')",
+			wantFilters: []client.QueryFilter{
+				{Key: "status", Operation: "IN", Value: list(*num("500"), *num("502"), *num("503")), Type: "number"},
+				{Key: "region", Operation: "NOT_IN", Value: list(*str("eu-west-1")), Type: "string"},
+			},
+			wantCombination: "OR",
+		},
+		{
+			name: "quoted key and escapes",
+			expr: `"user agent" = 'it\'s a \\ test' AND "and" = 'x'`,
+			wantFilters: []client.QueryFilter{
+				{Key: "user agent", Operation: "=", Value: str(`it's a \ test`), Type: "string"},
+				{Key: "and", Operation: "=", Value: str("x"), Type: "string"},
+			},
+			wantCombination: "AND",
+		},
+		{
+			name: "list with commas",
+			expr: "region IN ('eu-west-1,us-east-1', 'a')",
+			wantFilters: []client.QueryFilter{
+				{Key: "region", Operation: "IN", Value: list(*str("eu-west-1,us-east-1"), *str("a")), Type: "string"},
+			},
+		},
+		{
+			name:        "number as a string",
+			expr:        "code = '500'",
+			wantFilters: []client.QueryFilter{{Key: "code", Operation: "=", Value: str("500"), Type: "string"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, combination, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(filters, tt.wantFilters) {
+				t.Errorf("Parse(%q) filters = %+v, want %+v", tt.expr, filters, tt.wantFilters)
+			}
+			if combination != tt.wantCombination {
+				t.Errorf("Parse(%q) combination = %q, want %q", tt.expr, combination, tt.wantCombination)
+			}
+		})
+	}
+}
+
+func TestParse_ErrorsMultiline(t *testing.T) {
+	tests := []struct {
+		expr       string
+		wantLine   int
+		wantColumn int
+		wantErr    string
+	}{
+		{"a = 1 AND\nb = #\n", 2, 5, `unexpected character '#' at line 2, column 5`},
+		{"a = 1 AND\r\n  b = 'x' OR c = 3\r\n", 2, 11, `cannot combine AND and OR in the same expression at line 2, column 11`},
+		{"a = 1 AND\nb =\n\n", 2, 4, `expected a value after =, found end of expression at line 2, column 4`},
+		{"a = #\n", 1, 5, `unexpected character '#' at column 5`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, _, err := Parse(tt.expr)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error = %v, want a *SyntaxError", tt.expr, err)
+			}
+			if syntaxErr.Line != tt.wantLine || syntaxErr.Column != tt.wantColumn {
+				t.Errorf("Parse(%q) position = %d:%d, want %d:%d", tt.expr, syntaxErr.Line, syntaxErr.Column, tt.wantLine, tt.wantColumn)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("Parse(%q) error = %q, want %q", tt.expr, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		expr       string
+		wantColumn int
+		wantErr    string
+	}{
+		{"", 1, `expected a field key, found end of expression at column 1`},
+		{"message", 8, `expected an operator after "message", found end of expression at column 8`},
+		{"message CONTAINS 'x'", 9, `expected an operator after "message", found "CONTAINS" at column 9`},
+		{"message = ", 11, `expected a value after =, found end of expression at column 11`},
+		{"message = error", 11, `expected a value after =, found "error" at column 11`},
+		{"message = 'error", 11, `unterminated quoted string at column 11`},
+		{"a = 1 AND b = 2 OR c = 3", 17, `cannot combine AND and OR in the same expression at column 17`},
+		{"a = 1 b = 2", 7, `expected AND, OR or end of expression, found "b" at column 7`},
+		{"a EXISTS 'x'", 10, `expected AND, OR or end of expression, found 'x' at column 10`},
+		{"AND = 1", 1, `expected a field key, found "AND" at column 1`},
+		{"a ! 1", 3, `unexpected "!", expected "!=" at column 3`},
+		{"a = 1.", 5, `invalid number "1." at column 5`},
+		{"a = #", 5, `unexpected character '#' at column 5`},
+		{"a IN 1", 6, `expected "(" after IN, found "1" at column 6`},
+		{"a IN (1, 'x')", 10, `expected a number value like the others in the list, found 'x' at column 10`},
+		{"a = 007", 5, `invalid number "007" at column 5`},
+		{"a IN (1 2)", 9, `expected "," or ")", found "2" at column 9`},
+		{`"ünïcode" = 1 AND x = #`, 23, `unexpected character '#' at column 23`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, _, err := Parse(tt.expr)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error = %v, want a *SyntaxError", tt.expr, err)
+			}
+			if syntaxErr.Column != tt.wantColumn {
+				t.Errorf("Parse(%q) column = %d, want %d", tt.expr, syntaxErr.Column, tt.wantColumn)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("Parse(%q) error = %q, want %q", tt.expr, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,24 +1,42 @@
 package models
 
 import (
+	"context"
 	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 type AlertResourceModel struct {
-	Name        types.String    `tfsdk:"name"`
-	Description types.String    `tfsdk:"description"`
-	Enabled     types.Bool      `tfsdk:"enabled"`
-	Channels    []AlertChannel  `tfsdk:"channels"`
-	Query       types.String    `tfsdk:"query"`
-	Threshold   *AlertThreshold `tfsdk:"threshold"`
-	Frequency   types.String    `tfsdk:"frequency"`
-	Window      types.String    `tfsdk:"window"`
+	Name           types.String         `tfsdk:"name"`
+	Description    types.String         `tfsdk:"description"`
+	Enabled        types.Bool           `tfsdk:"enabled"`
+	Channels       types.List           `tfsdk:"channels"`
+	Query          types.String         `tfsdk:"query"`
+	Threshold      types.Object         `tfsdk:"threshold"`
+	Frequency      types.String         `tfsdk:"frequency"`
+	Window         types.String         `tfsdk:"window"`
+	ParametersJSON jsontypes.Normalized `tfsdk:"parameters_json"`
+	Id             types.String         `tfsdk:"id"`
+	CreatedAt      types.String         `tfsdk:"created_at"`
+	UpdatedAt      types.String         `tfsdk:"updated_at"`
+	CreatedBy      types.String         `tfsdk:"created_by"`
+	URL            types.String         `tfsdk:"url"`
+	Timeouts       timeouts.Value       `tfsdk:"timeouts"`
 }
 
 type AlertChannel struct {
-	Type    types.String   `tfsdk:"type"`
-	Targets []types.String `tfsdk:"targets"`
+	Type    types.String `tfsdk:"type"`
+	Targets types.List   `tfsdk:"targets"`
+}
+
+// AlertChannelAttrTypes are the attribute types of a channel.
+var AlertChannelAttrTypes = map[string]attr.Type{
+	"type":    types.StringType,
+	"targets": types.ListType{ElemType: types.StringType},
 }
 
 type AlertThreshold struct {
@@ -26,62 +44,138 @@ type AlertThreshold struct {
 	Value    types.Number `tfsdk:"value"`
 }
 
-func (a *AlertResourceModel) ToApiModel() *client.Alert {
-	return &client.Alert{
+// AlertThresholdAttrTypes are the attribute types of a threshold.
+var AlertThresholdAttrTypes = map[string]attr.Type{
+	"operator": types.StringType,
+	"value":    types.NumberType,
+}
+
+// NewAlertResourceModel returns an alert whose attributes are all null, as when none of
+// them is set.
+func NewAlertResourceModel() AlertResourceModel {
+	return AlertResourceModel{
+		Channels:       types.ListNull(types.ObjectType{AttrTypes: AlertChannelAttrTypes}),
+		Threshold:      types.ObjectNull(AlertThresholdAttrTypes),
+		ParametersJSON: jsontypes.NewNormalizedNull(),
+	}
+}
+
+// ToApiModel converts the alert. Values not known yet are left out.
+func (a *AlertResourceModel) ToApiModel(ctx context.Context) (*client.Alert, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var channels []AlertChannel
+	objectsAs(ctx, a.Channels, &channels, &diags)
+	var threshold AlertThreshold
+	objectAs(ctx, a.Threshold, &threshold, &diags)
+	alert := &client.Alert{
 		Parameters: client.AlertParameters{
 			QueryId: a.Query.ValueString(),
 			Threshold: client.AlertThreshold{
-				Operation: a.Threshold.Operator.ValueString(),
-				Value:     a.Threshold.Value.ValueBigFloat(),
+				Operation: threshold.Operator.ValueString(),
+				Value:     threshold.Value.ValueBigFloat(),
 			},
 			Frequency: a.Frequency.ValueString(),
 			Window:    a.Window.ValueString(),
+			Extra:     ParametersJSONToApiModel(a.ParametersJSON),
 		},
 		Id:          a.Name.ValueString(),
 		Description: a.Description.ValueString(),
 		Enabled:     a.Enabled.ValueBool(),
-		Channels: func() []client.AlertChannel {
-			channels := make([]client.AlertChannel, len(a.Channels))
-			for i, channel := range a.Channels {
-				channels[i] = client.AlertChannel{
-					Type: channel.Type.ValueString(),
-					Targets: func() []string {
-						targets := make([]string, len(channel.Targets))
-						for i, target := range channel.Targets {
-							targets[i] = target.ValueString()
-						}
-						return targets
-					}(),
-				}
-			}
-			return channels
-		}(),
+		Channels:    make([]client.AlertChannel, 0, len(channels)),
+	}
+	for _, channel := range channels {
+		targets := stringsOf(channel.Targets)
+		if targets == nil {
+			targets = []string{}
+		}
+		alert.Channels = append(alert.Channels, client.AlertChannel{
+			Type:    channel.Type.ValueString(),
+			Targets: targets,
+		})
 	}
+	return alert, diags
 }
 
-func (a *AlertResourceModel) FromApiModel(alert *client.Alert) {
+// FromApiMetadata sets the attributes computed by the API.
+func (a *AlertResourceModel) FromApiMetadata(obj *client.Alert) {
+	a.Id = types.StringValue(obj.Id)
+	a.CreatedAt = stringOrNull(obj.Created)
+	a.UpdatedAt = stringOrNull(obj.Updated)
+	a.CreatedBy = stringOrNull(obj.UserId)
+	a.URL = stringOrNull(obj.ConsoleURL())
+}
+
+func (a *AlertResourceModel) FromApiModel(ctx context.Context, alert *client.Alert) diag.Diagnostics {
+	var diags diag.Diagnostics
+	a.FromApiMetadata(alert)
 	a.Name = types.StringValue(alert.Id)
 	a.Description = types.StringValue(alert.Description)
 	a.Enabled = types.BoolValue(alert.Enabled)
-	a.Channels = func() []AlertChannel {
-		channels := make([]AlertChannel, len(alert.Channels))
-		for i, channel := range alert.Channels {
-			channels[i] = AlertChannel{
-				Type: types.StringValue(channel.Type),
-				Targets: func() []types.String {
-					targets := make([]types.String, len(channel.Targets))
-					for i, target := range channel.Targets {
-						targets[i] = types.StringValue(target)
-					}
-					return targets
-				}(),
-			}
+	channels := make([]AlertChannel, 0, len(alert.Channels))
+	for i, channel := range alert.Channels {
+		targets := channel.Targets
+		if targets == nil {
+			targets = []string{}
 		}
-		return channels
-	}()
-	a.Threshold.Operator = types.StringValue(alert.Parameters.Threshold.Operation)
-	a.Threshold.Value = types.NumberValue(alert.Parameters.Threshold.Value)
+		channels = append(channels, AlertChannel{
+			Type:    types.StringValue(channel.Type),
+			Targets: listKeepingNull(stringList(targets), elementAttribute(a.Channels, i, "targets")),
+		})
+	}
+	a.Channels = objectList(ctx, AlertChannelAttrTypes, channels, &diags)
+	a.Threshold = objectValue(ctx, AlertThresholdAttrTypes, &AlertThreshold{
+		Operator: types.StringValue(alert.Parameters.Threshold.Operation),
+		Value:    types.NumberValue(alert.Parameters.Threshold.Value),
+	}, &diags)
 	a.Frequency = types.StringValue(alert.Parameters.Frequency)
 	a.Window = types.StringValue(alert.Parameters.Window)
 	a.Query = types.StringValue(alert.Parameters.QueryId)
+	a.ParametersJSON = ParametersJSONFromApiModel(alert.Parameters, a.ParametersJSON)
+	return diags
+}
+
+// AlertDataSourceModel describes the data source data model.
+type AlertDataSourceModel struct {
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Enabled     types.Bool   `tfsdk:"enabled"`
+	Channels    types.List   `tfsdk:"channels"`
+	Query       types.String `tfsdk:"query"`
+	Threshold   types.Object `tfsdk:"threshold"`
+	Frequency   types.String `tfsdk:"frequency"`
+	Window      types.String `tfsdk:"window"`
+	Id          types.String `tfsdk:"id"`
+	CreatedAt   types.String `tfsdk:"created_at"`
+	UpdatedAt   types.String `tfsdk:"updated_at"`
+	CreatedBy   types.String `tfsdk:"created_by"`
+	URL         types.String `tfsdk:"url"`
+}
+
+func (a *AlertDataSourceModel) FromApiModel(ctx context.Context, alert *client.Alert) diag.Diagnostics {
+	m := NewAlertResourceModel()
+	diags := m.FromApiModel(ctx, alert)
+	a.Name = m.Name
+	a.Description = m.Description
+	a.Enabled = m.Enabled
+	a.Channels = m.Channels
+	a.Query = m.Query
+	a.Threshold = m.Threshold
+	a.Frequency = m.Frequency
+	a.Window = m.Window
+	a.Id = m.Id
+	a.CreatedAt = m.CreatedAt
+	a.UpdatedAt = m.UpdatedAt
+	a.CreatedBy = m.CreatedBy
+	a.URL = m.URL
+	return diags
+}
+
+// AlertsDataSourceModel describes the data source data model.
+type AlertsDataSourceModel struct {
+	NameRegex           types.String           `tfsdk:"name_regex"`
+	DescriptionContains types.String           `tfsdk:"description_contains"`
+	ChannelType         types.String           `tfsdk:"channel_type"`
+	ChannelTarget       types.String           `tfsdk:"channel_target"`
+	Names               []string               `tfsdk:"names"`
+	Alerts              []AlertDataSourceModel `tfsdk:"alerts"`
 }
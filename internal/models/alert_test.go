@@ -0,0 +1,223 @@
+package models
+
+import (
+	"context"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func testAlertChannel(typ string, targets types.List) attr.Value {
+	return types.ObjectValueMust(AlertChannelAttrTypes, map[string]attr.Value{
+		"type":    types.StringValue(typ),
+		"targets": targets,
+	})
+}
+
+func testAlertModel() AlertResourceModel {
+	m := NewAlertResourceModel()
+	m.Name = types.StringValue("errors")
+	m.Description = types.StringValue("Too many errors")
+	m.Enabled = types.BoolValue(true)
+	m.Channels = types.ListValueMust(types.ObjectType{AttrTypes: AlertChannelAttrTypes}, []attr.Value{
+		testAlertChannel("email", stringList([]string{"alerts@example.com"})),
+	})
+	m.Query = types.StringValue("api-errors")
+	m.Threshold = types.ObjectValueMust(AlertThresholdAttrTypes, map[string]attr.Value{
+		"operator": types.StringValue(">"),
+		"value":    types.NumberValue(big.NewFloat(10)),
+	})
+	m.Frequency = types.StringValue("5m")
+	m.Window = types.StringValue("15m")
+	return m
+}
+
+func testAlert() *client.Alert {
+	return &client.Alert{
+		Id:          "errors",
+		Description: "Too many errors",
+		Enabled:     true,
+		Channels:    []client.AlertChannel{{Type: "email", Targets: []string{"alerts@example.com"}}},
+		Parameters: client.AlertParameters{
+			QueryId:   "api-errors",
+			Threshold: client.AlertThreshold{Operation: ">", Value: big.NewFloat(10)},
+			Frequency: "5m",
+			Window:    "15m",
+		},
+	}
+}
+
+func TestAlertResourceModel_ToApiModel(t *testing.T) {
+	channelsType := types.ObjectType{AttrTypes: AlertChannelAttrTypes}
+	tests := []struct {
+		name   string
+		modify func(m *AlertResourceModel)
+		want   func(a *client.Alert)
+	}{
+		{
+			name:   "known",
+			modify: func(m *AlertResourceModel) {},
+			want:   func(a *client.Alert) {},
+		},
+		{
+			name:   "unknown channels",
+			modify: func(m *AlertResourceModel) { m.Channels = types.ListUnknown(channelsType) },
+			want:   func(a *client.Alert) { a.Channels = []client.AlertChannel{} },
+		},
+		{
+			name: "unknown channel",
+			modify: func(m *AlertResourceModel) {
+				m.Channels = types.ListValueMust(channelsType, []attr.Value{
+					types.ObjectUnknown(AlertChannelAttrTypes),
+					testAlertChannel("slack", stringList([]string{"#alerts"})),
+				})
+			},
+			want: func(a *client.Alert) {
+				a.Channels = []client.AlertChannel{{Type: "slack", Targets: []string{"#alerts"}}}
+			},
+		},
+		{
+			name: "unknown targets",
+			modify: func(m *AlertResourceModel) {
+				m.Channels = types.ListValueMust(channelsType, []attr.Value{
+					testAlertChannel("email", types.ListUnknown(types.StringType)),
+				})
+			},
+			want: func(a *client.Alert) { a.Channels[0].Targets = []string{} },
+		},
+		{
+			name: "unknown target",
+			modify: func(m *AlertResourceModel) {
+				m.Channels = types.ListValueMust(channelsType, []attr.Value{
+					testAlertChannel("email", types.ListValueMust(types.StringType, []attr.Value{
+						types.StringUnknown(),
+						types.StringValue("oncall@example.com"),
+					})),
+				})
+			},
+			want: func(a *client.Alert) { a.Channels[0].Targets = []string{"oncall@example.com"} },
+		},
+		{
+			name:   "unknown threshold",
+			modify: func(m *AlertResourceModel) { m.Threshold = types.ObjectUnknown(AlertThresholdAttrTypes) },
+			want:   func(a *client.Alert) { a.Parameters.Threshold = client.AlertThreshold{} },
+		},
+		{
+			name: "unknown threshold value",
+			modify: func(m *AlertResourceModel) {
+				m.Threshold = types.ObjectValueMust(AlertThresholdAttrTypes, map[string]attr.Value{
+					"operator": types.StringValue(">"),
+					"value":    types.NumberUnknown(),
+				})
+			},
+			want: func(a *client.Alert) { a.Parameters.Threshold.Value = nil },
+		},
+		{
+			name:   "null threshold",
+			modify: func(m *AlertResourceModel) { m.Threshold = types.ObjectNull(AlertThresholdAttrTypes) },
+			want:   func(a *client.Alert) { a.Parameters.Threshold = client.AlertThreshold{} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testAlertModel()
+			tt.modify(&m)
+			want := testAlert()
+			tt.want(want)
+			got, diags := m.ToApiModel(context.Background())
+			if diags.HasError() {
+				t.Fatalf("ToApiModel() diagnostics: %v", diags)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ToApiModel() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestAlertResourceModel_FromApiModel(t *testing.T) {
+	channelsType := types.ObjectType{AttrTypes: AlertChannelAttrTypes}
+	emptyTargets := types.ListValueMust(types.StringType, []attr.Value{})
+	tests := []struct {
+		name  string
+		prior func(m *AlertResourceModel)
+		api   func(a *client.Alert)
+		want  func(m *AlertResourceModel)
+	}{
+		{
+			name:  "import",
+			prior: func(m *AlertResourceModel) {},
+			api:   func(a *client.Alert) {},
+			want:  func(m *AlertResourceModel) {},
+		},
+		{
+			name: "null targets",
+			prior: func(m *AlertResourceModel) {
+				m.Channels = types.ListValueMust(channelsType, []attr.Value{
+					testAlertChannel("email", types.ListNull(types.StringType)),
+				})
+			},
+			api: func(a *client.Alert) { a.Channels[0].Targets = nil },
+			want: func(m *AlertResourceModel) {
+				m.Channels = types.ListValueMust(channelsType, []attr.Value{
+					testAlertChannel("email", types.ListNull(types.StringType)),
+				})
+			},
+		},
+		{
+			name: "empty targets",
+			prior: func(m *AlertResourceModel) {
+				m.Channels = types.ListValueMust(channelsType, []attr.Value{
+					testAlertChannel("email", emptyTargets),
+				})
+			},
+			api: func(a *client.Alert) { a.Channels[0].Targets = []string{} },
+			want: func(m *AlertResourceModel) {
+				m.Channels = types.ListValueMust(channelsType, []attr.Value{
+					testAlertChannel("email", emptyTargets),
+				})
+			},
+		},
+		{
+			name:  "targets of a channel the state does not have",
+			prior: func(m *AlertResourceModel) {},
+			api:   func(a *client.Alert) { a.Channels[0].Targets = nil },
+			want: func(m *AlertResourceModel) {
+				m.Channels = types.ListValueMust(channelsType, []attr.Value{
+					testAlertChannel("email", emptyTargets),
+				})
+			},
+		},
+		{
+			name: "targets added outside Terraform",
+			prior: func(m *AlertResourceModel) {
+				m.Channels = types.ListValueMust(channelsType, []attr.Value{
+					testAlertChannel("email", types.ListNull(types.StringType)),
+				})
+			},
+			api:  func(a *client.Alert) {},
+			want: func(m *AlertResourceModel) {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewAlertResourceModel()
+			tt.prior(&m)
+			alert := testAlert()
+			tt.api(alert)
+			if diags := m.FromApiModel(context.Background(), alert); diags.HasError() {
+				t.Fatalf("FromApiModel() diagnostics: %v", diags)
+			}
+			want := testAlertModel()
+			want.Id = types.StringValue("errors")
+			want.URL = m.URL
+			tt.want(&want)
+			if !reflect.DeepEqual(m, want) {
+				t.Errorf("FromApiModel() = %+v, want %+v", m, want)
+			}
+		})
+	}
+}
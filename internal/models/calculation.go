@@ -0,0 +1,80 @@
+package models
+
+// CalculationParameter is whether a calculation operator takes a parameter.
+type CalculationParameter int
+
+const (
+	CalculationParameterNone CalculationParameter = iota
+	CalculationParameterOptional
+	CalculationParameterRequired
+)
+
+// QueryCalculationOperator describes an operator of query calculations.
+type QueryCalculationOperator struct {
+	Name string
+	// Description says what the operator calculates.
+	Description string
+	NeedsKey    bool
+	Parameter   CalculationParameter
+	// ParameterMin and ParameterMax are the exclusive bounds of the parameter.
+	ParameterMin, ParameterMax float64
+}
+
+// QueryCalculationOperatorSpecs are the operators accepted by the API in the
+// calculations of a query.
+var QueryCalculationOperatorSpecs = []QueryCalculationOperator{
+	{Name: "COUNT", Description: "the number of matching events"},
+	{Name: "COUNT_DISTINCT", Description: "the number of distinct values of the field", NeedsKey: true},
+	{Name: "SUM", Description: "the sum of the field", NeedsKey: true},
+	{Name: "AVG", Description: "the average of the field", NeedsKey: true},
+	{Name: "MAX", Description: "the largest value of the field", NeedsKey: true},
+	{Name: "MIN", Description: "the smallest value of the field", NeedsKey: true},
+	{Name: "MEDIAN", Description: "the median of the field, the same as `P50`", NeedsKey: true},
+	{Name: "STDDEV", Description: "the standard deviation of the field", NeedsKey: true},
+	{Name: "VARIANCE", Description: "the variance of the field", NeedsKey: true},
+	{Name: "P001", Description: "the 0.1st percentile of the field", NeedsKey: true},
+	{Name: "P01", Description: "the 1st percentile of the field", NeedsKey: true},
+	{Name: "P05", Description: "the 5th percentile of the field", NeedsKey: true},
+	{Name: "P10", Description: "the 10th percentile of the field", NeedsKey: true},
+	{Name: "P25", Description: "the 25th percentile of the field", NeedsKey: true},
+	{Name: "P50", Description: "the 50th percentile of the field", NeedsKey: true},
+	{Name: "P75", Description: "the 75th percentile of the field", NeedsKey: true},
+	{Name: "P90", Description: "the 90th percentile of the field", NeedsKey: true},
+	{Name: "P95", Description: "the 95th percentile of the field", NeedsKey: true},
+	{Name: "P99", Description: "the 99th percentile of the field", NeedsKey: true},
+	{Name: "P999", Description: "the 99.9th percentile of the field", NeedsKey: true},
+	{
+		Name:         "PERCENTILE",
+		Description:  "the percentile of the field given by `parameter`, between 0 and 100",
+		NeedsKey:     true,
+		Parameter:    CalculationParameterRequired,
+		ParameterMin: 0,
+		ParameterMax: 100,
+	},
+	{
+		Name:         "RATE",
+		Description:  "the number of matching events per second, or per `parameter` seconds, less than a day",
+		Parameter:    CalculationParameterOptional,
+		ParameterMin: 0,
+		ParameterMax: 86400,
+	},
+}
+
+// QueryCalculationOperators are the names of QueryCalculationOperatorSpecs.
+var QueryCalculationOperators = func() []string {
+	names := make([]string, len(QueryCalculationOperatorSpecs))
+	for i, op := range QueryCalculationOperatorSpecs {
+		names[i] = op.Name
+	}
+	return names
+}()
+
+// LookupQueryCalculationOperator returns the operator named name.
+func LookupQueryCalculationOperator(name string) (QueryCalculationOperator, bool) {
+	for _, op := range QueryCalculationOperatorSpecs {
+		if op.Name == name {
+			return op, true
+		}
+	}
+	return QueryCalculationOperator{}, false
+}
@@ -1,14 +1,26 @@
 package models
 
 import (
+	"context"
 	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 type DashboardResourceModel struct {
-	Name        types.String      `tfsdk:"name"`
-	Description types.String      `tfsdk:"description"`
-	Widgets     []DashboardWidget `tfsdk:"widgets"`
+	Name           types.String         `tfsdk:"name"`
+	Description    types.String         `tfsdk:"description"`
+	Widgets        types.List           `tfsdk:"widgets"`
+	ParametersJSON jsontypes.Normalized `tfsdk:"parameters_json"`
+	Id             types.String         `tfsdk:"id"`
+	CreatedAt      types.String         `tfsdk:"created_at"`
+	UpdatedAt      types.String         `tfsdk:"updated_at"`
+	CreatedBy      types.String         `tfsdk:"created_by"`
+	URL            types.String         `tfsdk:"url"`
+	Timeouts       timeouts.Value       `tfsdk:"timeouts"`
 }
 
 type DashboardWidget struct {
@@ -18,43 +30,106 @@ type DashboardWidget struct {
 	Description types.String `tfsdk:"description"`
 }
 
-func (d *DashboardResourceModel) ToApiModel() *client.Dashboard {
-	return &client.Dashboard{
+// DashboardWidgetAttrTypes are the attribute types of a widget.
+var DashboardWidgetAttrTypes = map[string]attr.Type{
+	"query_id":    types.StringType,
+	"type":        types.StringType,
+	"name":        types.StringType,
+	"description": types.StringType,
+}
+
+// NewDashboardResourceModel returns a dashboard whose attributes are all null, as when
+// none of them is set.
+func NewDashboardResourceModel() DashboardResourceModel {
+	return DashboardResourceModel{
+		Widgets:        types.ListNull(types.ObjectType{AttrTypes: DashboardWidgetAttrTypes}),
+		ParametersJSON: jsontypes.NewNormalizedNull(),
+	}
+}
+
+// ToApiModel converts the dashboard. Values not known yet are left out.
+func (d *DashboardResourceModel) ToApiModel(ctx context.Context) (*client.Dashboard, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var widgets []DashboardWidget
+	objectsAs(ctx, d.Widgets, &widgets, &diags)
+	dashboard := &client.Dashboard{
 		Parameters: client.DashboardParameters{
-			Widgets: func() []client.DashboardWidget {
-				widgets := make([]client.DashboardWidget, 0, len(d.Widgets))
-				for _, widget := range d.Widgets {
-					widgets = append(widgets, client.DashboardWidget{
-						QueryId:     widget.QueryId.ValueString(),
-						Type:        client.WidgetType(widget.Type.ValueString()),
-						Name:        widget.Name.ValueString(),
-						Description: widget.Description.ValueString(),
-					})
-				}
-				return widgets
-			}(),
+			Widgets: make([]client.DashboardWidget, 0, len(widgets)),
+			Extra:   ParametersJSONToApiModel(d.ParametersJSON),
 		},
 		Id:          d.Name.ValueString(),
 		Description: d.Description.ValueString(),
 	}
+	for _, widget := range widgets {
+		dashboard.Parameters.Widgets = append(dashboard.Parameters.Widgets, client.DashboardWidget{
+			QueryId:     widget.QueryId.ValueString(),
+			Type:        client.WidgetType(widget.Type.ValueString()),
+			Name:        widget.Name.ValueString(),
+			Description: widget.Description.ValueString(),
+		})
+	}
+	return dashboard, diags
 }
 
-func (d *DashboardResourceModel) FromApiModel(dashboard *client.Dashboard) {
-	if dashboard == nil {
-		return
-	}
+// FromApiMetadata sets the attributes computed by the API.
+func (d *DashboardResourceModel) FromApiMetadata(obj *client.Dashboard) {
+	d.Id = types.StringValue(obj.Id)
+	d.CreatedAt = stringOrNull(obj.Created)
+	d.UpdatedAt = stringOrNull(obj.Updated)
+	d.CreatedBy = stringOrNull(obj.UserId)
+	d.URL = stringOrNull(obj.ConsoleURL())
+}
+
+func (d *DashboardResourceModel) FromApiModel(ctx context.Context, dashboard *client.Dashboard) diag.Diagnostics {
+	var diags diag.Diagnostics
+	d.FromApiMetadata(dashboard)
 	d.Name = types.StringValue(dashboard.Id)
-	d.Description = types.StringValue(dashboard.Description)
-	d.Widgets = func() []DashboardWidget {
-		widgets := make([]DashboardWidget, 0, len(dashboard.Parameters.Widgets))
-		for _, widget := range dashboard.Parameters.Widgets {
-			widgets = append(widgets, DashboardWidget{
-				QueryId:     types.StringValue(widget.QueryId),
-				Type:        types.StringValue(string(widget.Type)),
-				Name:        types.StringValue(widget.Name),
-				Description: types.StringValue(widget.Description),
-			})
-		}
-		return widgets
-	}()
+	d.Description = stringKeepingNull(dashboard.Description, d.Description)
+	widgets := make([]DashboardWidget, 0, len(dashboard.Parameters.Widgets))
+	for i, widget := range dashboard.Parameters.Widgets {
+		widgets = append(widgets, DashboardWidget{
+			QueryId:     types.StringValue(widget.QueryId),
+			Type:        types.StringValue(string(widget.Type)),
+			Name:        stringKeepingNull(widget.Name, elementAttribute(d.Widgets, i, "name")),
+			Description: stringKeepingNull(widget.Description, elementAttribute(d.Widgets, i, "description")),
+		})
+	}
+	d.Widgets = objectList(ctx, DashboardWidgetAttrTypes, widgets, &diags)
+	d.ParametersJSON = ParametersJSONFromApiModel(dashboard.Parameters, d.ParametersJSON)
+	return diags
+}
+
+// DashboardDataSourceModel describes the data source data model.
+type DashboardDataSourceModel struct {
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Widgets     types.List   `tfsdk:"widgets"`
+	Id          types.String `tfsdk:"id"`
+	CreatedAt   types.String `tfsdk:"created_at"`
+	UpdatedAt   types.String `tfsdk:"updated_at"`
+	CreatedBy   types.String `tfsdk:"created_by"`
+	URL         types.String `tfsdk:"url"`
+}
+
+func (d *DashboardDataSourceModel) FromApiModel(ctx context.Context, dashboard *client.Dashboard) diag.Diagnostics {
+	m := NewDashboardResourceModel()
+	diags := m.FromApiModel(ctx, dashboard)
+	d.Name = m.Name
+	d.Description = m.Description
+	d.Widgets = m.Widgets
+	d.Id = m.Id
+	d.CreatedAt = m.CreatedAt
+	d.UpdatedAt = m.UpdatedAt
+	d.CreatedBy = m.CreatedBy
+	d.URL = m.URL
+	return diags
+}
+
+// DashboardsDataSourceModel describes the data source data model.
+type DashboardsDataSourceModel struct {
+	NameRegex           types.String               `tfsdk:"name_regex"`
+	DescriptionContains types.String               `tfsdk:"description_contains"`
+	QueryId             types.String               `tfsdk:"query_id"`
+	Names               []string                   `tfsdk:"names"`
+	Dashboards          []DashboardDataSourceModel `tfsdk:"dashboards"`
 }
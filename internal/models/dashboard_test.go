@@ -0,0 +1,220 @@
+package models
+
+import (
+	"context"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"testing"
+)
+
+func testDashboardWidget(queryId string) attr.Value {
+	return types.ObjectValueMust(DashboardWidgetAttrTypes, map[string]attr.Value{
+		"query_id":    types.StringValue(queryId),
+		"type":        types.StringValue("timeseries"),
+		"name":        types.StringValue("Errors"),
+		"description": types.StringValue("Errors over time"),
+	})
+}
+
+func testDashboardModel() DashboardResourceModel {
+	m := NewDashboardResourceModel()
+	m.Name = types.StringValue("overview")
+	m.Description = types.StringValue("Service overview")
+	m.Widgets = types.ListValueMust(types.ObjectType{AttrTypes: DashboardWidgetAttrTypes}, []attr.Value{
+		testDashboardWidget("api-errors"),
+	})
+	return m
+}
+
+func testDashboard() *client.Dashboard {
+	return &client.Dashboard{
+		Id:          "overview",
+		Description: "Service overview",
+		Parameters: client.DashboardParameters{
+			Widgets: []client.DashboardWidget{{
+				QueryId:     "api-errors",
+				Type:        client.WidgetTypeTimeSeries,
+				Name:        "Errors",
+				Description: "Errors over time",
+			}},
+		},
+	}
+}
+
+func TestDashboardResourceModel_ToApiModel(t *testing.T) {
+	widgetsType := types.ObjectType{AttrTypes: DashboardWidgetAttrTypes}
+	tests := []struct {
+		name   string
+		modify func(m *DashboardResourceModel)
+		want   func(d *client.Dashboard)
+	}{
+		{
+			name:   "known",
+			modify: func(m *DashboardResourceModel) {},
+			want:   func(d *client.Dashboard) {},
+		},
+		{
+			name:   "unknown widgets",
+			modify: func(m *DashboardResourceModel) { m.Widgets = types.ListUnknown(widgetsType) },
+			want:   func(d *client.Dashboard) { d.Parameters.Widgets = []client.DashboardWidget{} },
+		},
+		{
+			name: "unknown widget",
+			modify: func(m *DashboardResourceModel) {
+				m.Widgets = types.ListValueMust(widgetsType, []attr.Value{
+					testDashboardWidget("api-errors"),
+					types.ObjectUnknown(DashboardWidgetAttrTypes),
+				})
+			},
+			want: func(d *client.Dashboard) {},
+		},
+		{
+			name: "unknown query",
+			modify: func(m *DashboardResourceModel) {
+				m.Widgets = types.ListValueMust(widgetsType, []attr.Value{
+					types.ObjectValueMust(DashboardWidgetAttrTypes, map[string]attr.Value{
+						"query_id":    types.StringUnknown(),
+						"type":        types.StringValue("timeseries"),
+						"name":        types.StringValue("Errors"),
+						"description": types.StringValue("Errors over time"),
+					}),
+				})
+			},
+			want: func(d *client.Dashboard) { d.Parameters.Widgets[0].QueryId = "" },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testDashboardModel()
+			tt.modify(&m)
+			want := testDashboard()
+			tt.want(want)
+			got, diags := m.ToApiModel(context.Background())
+			if diags.HasError() {
+				t.Fatalf("ToApiModel() diagnostics: %v", diags)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ToApiModel() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func testDashboardWidgetWith(name, description types.String) attr.Value {
+	return types.ObjectValueMust(DashboardWidgetAttrTypes, map[string]attr.Value{
+		"query_id":    types.StringValue("api-errors"),
+		"type":        types.StringValue("timeseries"),
+		"name":        name,
+		"description": description,
+	})
+}
+
+func TestDashboardResourceModel_FromApiModel(t *testing.T) {
+	widgetsType := types.ObjectType{AttrTypes: DashboardWidgetAttrTypes}
+	tests := []struct {
+		name  string
+		prior func(m *DashboardResourceModel)
+		api   func(d *client.Dashboard)
+		want  func(m *DashboardResourceModel)
+	}{
+		{
+			name:  "import",
+			prior: func(m *DashboardResourceModel) {},
+			api:   func(d *client.Dashboard) {},
+			want:  func(m *DashboardResourceModel) {},
+		},
+		{
+			name:  "null description",
+			prior: func(m *DashboardResourceModel) { m.Description = types.StringNull() },
+			api:   func(d *client.Dashboard) { d.Description = "" },
+			want:  func(m *DashboardResourceModel) { m.Description = types.StringNull() },
+		},
+		{
+			name:  "empty description",
+			prior: func(m *DashboardResourceModel) { m.Description = types.StringValue("") },
+			api:   func(d *client.Dashboard) { d.Description = "" },
+			want:  func(m *DashboardResourceModel) { m.Description = types.StringValue("") },
+		},
+		{
+			name:  "description removed outside Terraform",
+			prior: func(m *DashboardResourceModel) { m.Description = types.StringValue("Service overview") },
+			api:   func(d *client.Dashboard) { d.Description = "" },
+			want:  func(m *DashboardResourceModel) { m.Description = types.StringValue("") },
+		},
+		{
+			name: "null widget name and description",
+			prior: func(m *DashboardResourceModel) {
+				m.Widgets = types.ListValueMust(widgetsType, []attr.Value{
+					testDashboardWidgetWith(types.StringNull(), types.StringNull()),
+				})
+			},
+			api: func(d *client.Dashboard) {
+				d.Parameters.Widgets[0].Name = ""
+				d.Parameters.Widgets[0].Description = ""
+			},
+			want: func(m *DashboardResourceModel) {
+				m.Widgets = types.ListValueMust(widgetsType, []attr.Value{
+					testDashboardWidgetWith(types.StringNull(), types.StringNull()),
+				})
+			},
+		},
+		{
+			name: "empty widget name and description",
+			prior: func(m *DashboardResourceModel) {
+				m.Widgets = types.ListValueMust(widgetsType, []attr.Value{
+					testDashboardWidgetWith(types.StringValue(""), types.StringValue("")),
+				})
+			},
+			api: func(d *client.Dashboard) {
+				d.Parameters.Widgets[0].Name = ""
+				d.Parameters.Widgets[0].Description = ""
+			},
+			want: func(m *DashboardResourceModel) {
+				m.Widgets = types.ListValueMust(widgetsType, []attr.Value{
+					testDashboardWidgetWith(types.StringValue(""), types.StringValue("")),
+				})
+			},
+		},
+		{
+			name: "widget added outside Terraform",
+			prior: func(m *DashboardResourceModel) {
+				m.Widgets = types.ListValueMust(widgetsType, []attr.Value{
+					testDashboardWidgetWith(types.StringNull(), types.StringNull()),
+				})
+			},
+			api: func(d *client.Dashboard) {
+				d.Parameters.Widgets[0].Name = ""
+				d.Parameters.Widgets = append(d.Parameters.Widgets, client.DashboardWidget{
+					QueryId: "api-errors",
+					Type:    client.WidgetTypeTimeSeries,
+				})
+			},
+			want: func(m *DashboardResourceModel) {
+				m.Widgets = types.ListValueMust(widgetsType, []attr.Value{
+					testDashboardWidgetWith(types.StringNull(), types.StringValue("Errors over time")),
+					testDashboardWidgetWith(types.StringValue(""), types.StringValue("")),
+				})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewDashboardResourceModel()
+			tt.prior(&m)
+			dashboard := testDashboard()
+			tt.api(dashboard)
+			if diags := m.FromApiModel(context.Background(), dashboard); diags.HasError() {
+				t.Fatalf("FromApiModel() diagnostics: %v", diags)
+			}
+			want := testDashboardModel()
+			want.Id = types.StringValue("overview")
+			want.URL = m.URL
+			tt.want(&want)
+			if !reflect.DeepEqual(m, want) {
+				t.Errorf("FromApiModel() = %+v, want %+v", m, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// MaxFilterGroupDepth is the number of levels of filter_group blocks. Terraform schemas
+// cannot be recursive, so filter groups are declared down to this depth, where they
+// contain filters only.
+const MaxFilterGroupDepth = 3
+
+// QueryFilterAttrTypes are the attribute types of a filter.
+var QueryFilterAttrTypes = map[string]attr.Type{
+	"key":       types.StringType,
+	"operation": types.StringType,
+	"value":     types.StringType,
+	"values":    types.ListType{ElemType: types.StringType},
+	"type":      types.StringType,
+}
+
+// FilterGroupAttrTypes returns the attribute types of a filter group at depth, starting
+// from 1 for the groups of a query.
+func FilterGroupAttrTypes(depth int) map[string]attr.Type {
+	attrTypes := map[string]attr.Type{
+		"filter_combination": types.StringType,
+		"filters":            types.ListType{ElemType: types.ObjectType{AttrTypes: QueryFilterAttrTypes}},
+	}
+	if depth < MaxFilterGroupDepth {
+		attrTypes["filter_group"] = types.ListType{ElemType: types.ObjectType{AttrTypes: FilterGroupAttrTypes(depth + 1)}}
+	}
+	return attrTypes
+}
+
+// FilterGroupsToApiModel converts list, the filter groups at p. Values not known yet are
+// left out, and invalid filter values are reported at their path.
+func FilterGroupsToApiModel(ctx context.Context, list types.List, p path.Path, diags *diag.Diagnostics) []client.QueryFilterGroup {
+	groups := make([]client.QueryFilterGroup, 0, len(list.Elements()))
+	for i, elem := range list.Elements() {
+		obj, ok := elem.(types.Object)
+		if !ok || obj.IsNull() || obj.IsUnknown() {
+			continue
+		}
+		attrs := obj.Attributes()
+		group := client.QueryFilterGroup{}
+		if v, ok := attrs["filter_combination"].(types.String); ok {
+			group.FilterCombination = v.ValueString()
+		}
+		if filters, ok := attrs["filters"].(types.List); ok {
+			group.Filters = QueryFiltersToApiModel(ctx, filters, p.AtListIndex(i).AtName("filters"), diags)
+		}
+		if subgroups, ok := attrs["filter_group"].(types.List); ok && len(subgroups.Elements()) > 0 {
+			group.FilterGroups = FilterGroupsToApiModel(ctx, subgroups, p.AtListIndex(i).AtName("filter_group"), diags)
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// filterGroupDepth returns the number of levels of groups, 0 when there are none.
+func filterGroupDepth(groups []client.QueryFilterGroup) int {
+	depth := 0
+	for _, g := range groups {
+		if d := filterGroupDepth(g.FilterGroups) + 1; d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+// checkFilterGroupDepth adds an error when groups, the filter groups of a query returned
+// by the API, are nested deeper than MaxFilterGroupDepth. The state could not hold the
+// deeper groups, and the next update would delete them.
+func checkFilterGroupDepth(groups []client.QueryFilterGroup, diags *diag.Diagnostics) {
+	if depth := filterGroupDepth(groups); depth > MaxFilterGroupDepth {
+		diags.AddAttributeError(path.Root("filter_group"), "Filter Groups Nested Too Deeply",
+			fmt.Sprintf("The query has filter groups nested %d levels deep, but the provider supports at most %d levels. "+
+				"Flatten the filter groups of the query in the Baselime console before using it in Terraform.", depth, MaxFilterGroupDepth))
+	}
+}
+
+// warnFilterGroupDepth adds a warning when groups, the filter groups of the query name
+// read by a data source, are nested deeper than MaxFilterGroupDepth. Reading the query
+// leaves the deeper groups out, but does not change it.
+func warnFilterGroupDepth(name string, groups []client.QueryFilterGroup, diags *diag.Diagnostics) {
+	if depth := filterGroupDepth(groups); depth > MaxFilterGroupDepth {
+		diags.AddWarning("Filter Groups Nested Too Deeply",
+			fmt.Sprintf("The query %q has filter groups nested %d levels deep, but the provider supports at most %d levels. "+
+				"The groups nested deeper are left out of the data source.", name, depth, MaxFilterGroupDepth))
+	}
+}
+
+// FilterGroupsFromApiModel converts the filter groups of a query returned by the API into
+// a list of groups at depth. Groups nested deeper than MaxFilterGroupDepth are left out,
+// see checkFilterGroupDepth and warnFilterGroupDepth.
+func FilterGroupsFromApiModel(groups []client.QueryFilterGroup, depth int) types.List {
+	attrTypes := FilterGroupAttrTypes(depth)
+	elems := make([]attr.Value, 0, len(groups))
+	for _, g := range groups {
+		// filters are optional in groups made of other groups
+		filters := types.ListNull(types.ObjectType{AttrTypes: QueryFilterAttrTypes})
+		if len(g.Filters) > 0 {
+			filters = QueryFiltersFromApiModel(g.Filters)
+		}
+		attrs := map[string]attr.Value{
+			"filter_combination": types.StringValue(g.FilterCombination),
+			"filters":            filters,
+		}
+		if depth < MaxFilterGroupDepth {
+			attrs["filter_group"] = FilterGroupsFromApiModel(g.FilterGroups, depth+1)
+		}
+		elems = append(elems, types.ObjectValueMust(attrTypes, attrs))
+	}
+	return types.ListValueMust(types.ObjectType{AttrTypes: attrTypes}, elems)
+}
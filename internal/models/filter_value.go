@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// QueryFilterValueToApiModel returns the value of a filter, checked against its
+// operation and its type: none for EXISTS and DOES_NOT_EXIST, the list of values for IN
+// and NOT_IN, and value, left out when empty, for the others. When the value is invalid,
+// the error says why and the value returned keeps what could be read of it.
+func QueryFilterValueToApiModel(operation, typ, value string, values types.List) (*client.QueryFilterValue, error) {
+	switch operation {
+	case "EXISTS", "DOES_NOT_EXIST":
+		if value != "" || !values.IsNull() {
+			return nil, fmt.Errorf("%s filters take no value", operation)
+		}
+		return nil, nil
+	case "IN", "NOT_IN":
+		list := make([]client.QueryFilterValue, 0, len(values.Elements()))
+		var err error
+		for i, elem := range values.Elements() {
+			s, _ := elem.(types.String)
+			v, elemErr := scalarFilterValue(s.ValueString(), typ)
+			if elemErr != nil && err == nil {
+				err = fmt.Errorf("values[%d]: %w", i, elemErr)
+			}
+			list = append(list, v)
+		}
+		result := client.ListFilterValue(list...)
+		switch {
+		case value != "":
+			return &result, fmt.Errorf("%s filters take a list of values in values rather than value", operation)
+		case len(list) == 0:
+			return &result, fmt.Errorf("%s filters need at least one value in values", operation)
+		}
+		return &result, err
+	}
+	if !values.IsNull() {
+		err := fmt.Errorf("values is only used by IN and NOT_IN filters, %s filters take a single value in value", operation)
+		if value == "" {
+			return nil, err
+		}
+		v, _ := scalarFilterValue(value, typ)
+		return &v, err
+	}
+	if value == "" && (typ == "" || typ == "string") {
+		return nil, nil
+	}
+	v, err := scalarFilterValue(value, typ)
+	return &v, err
+}
+
+// scalarFilterValue returns s as a value of type typ. Invalid values are returned as
+// strings, with an error.
+func scalarFilterValue(s, typ string) (client.QueryFilterValue, error) {
+	switch typ {
+	case "number":
+		if v, err := client.NumberFilterValue(s); err == nil {
+			return v, nil
+		}
+		return client.StringFilterValue(s), fmt.Errorf("%q is not a number", s)
+	case "boolean":
+		if s == "true" || s == "false" {
+			return client.BoolFilterValue(s == "true"), nil
+		}
+		return client.StringFilterValue(s), fmt.Errorf("%q is not a boolean, expected true or false", s)
+	}
+	return client.StringFilterValue(s), nil
+}
+
+// queryFilterValueFromApiModel returns the value and the values of a filter returned by
+// the API.
+func queryFilterValueFromApiModel(v *client.QueryFilterValue) (types.String, types.List) {
+	if v == nil {
+		return types.StringValue(""), types.ListNull(types.StringType)
+	}
+	if v.Kind() != client.FilterValueList {
+		return types.StringValue(v.String()), types.ListNull(types.StringType)
+	}
+	elems := make([]attr.Value, 0, len(v.List()))
+	for _, e := range v.List() {
+		elems = append(elems, types.StringValue(e.String()))
+	}
+	return types.StringValue(""), types.ListValueMust(types.StringType, elems)
+}
+
+// withPriorFilterValues returns filters, with the ones equal to the filter at the same
+// position in prior replaced by it. Numbers then keep the form they were written in.
+func withPriorFilterValues(filters, prior []client.QueryFilter) []client.QueryFilter {
+	result := make([]client.QueryFilter, len(filters))
+	copy(result, filters)
+	for i := range result {
+		if i < len(prior) && result[i].Equal(prior[i]) {
+			result[i] = prior[i]
+		}
+	}
+	return result
+}
+
+// withPriorFilterGroupValues is withPriorFilterValues for the filters of groups.
+func withPriorFilterGroupValues(groups, prior []client.QueryFilterGroup) []client.QueryFilterGroup {
+	result := make([]client.QueryFilterGroup, len(groups))
+	copy(result, groups)
+	for i := range result {
+		if i < len(prior) {
+			result[i].Filters = withPriorFilterValues(result[i].Filters, prior[i].Filters)
+			result[i].FilterGroups = withPriorFilterGroupValues(result[i].FilterGroups, prior[i].FilterGroups)
+		}
+	}
+	return result
+}
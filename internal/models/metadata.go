@@ -0,0 +1,12 @@
+package models
+
+import "github.com/hashicorp/terraform-plugin-framework/types"
+
+// stringOrNull converts an optional field of an API object, which is empty when the API
+// does not return it.
+func stringOrNull(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+)
+
+// ParametersJSONToApiModel returns the parameters of parameters_json. Null, unknown and
+// invalid values have none.
+func ParametersJSONToApiModel(value jsontypes.Normalized) client.ExtraParameters {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	extra, err := DecodeParametersJSON(value.ValueString())
+	if err != nil {
+		return nil
+	}
+	return extra
+}
+
+// DecodeParametersJSON decodes a parameters_json value, which must be a JSON object.
+// Numbers are kept as written.
+func DecodeParametersJSON(s string) (client.ExtraParameters, error) {
+	var extra client.ExtraParameters
+	d := json.NewDecoder(bytes.NewReader([]byte(s)))
+	d.UseNumber()
+	if err := d.Decode(&extra); err != nil {
+		return nil, err
+	}
+	if extra == nil {
+		return nil, errors.New("parameters must be a JSON object")
+	}
+	return extra, nil
+}
+
+// ParametersJSONFromApiModel returns the parameters_json of an object whose parameters
+// were read as params, prior being the parameters_json in the state or the plan. Only the
+// parameters of prior are kept, so that the ones set by other attributes or by the API
+// are left out. Parameters of prior the API did not return are left out too.
+func ParametersJSONFromApiModel(params interface{}, prior jsontypes.Normalized) jsontypes.Normalized {
+	if prior.IsNull() || prior.IsUnknown() {
+		return jsontypes.NewNormalizedNull()
+	}
+	priorParams, err := DecodeParametersJSON(prior.ValueString())
+	if err != nil {
+		return prior
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return prior
+	}
+	var received map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&received); err != nil {
+		return prior
+	}
+	projected := projectParameters(received, priorParams)
+	// parameters the API returns unchanged keep the form they were written in, such as
+	// 0.50 rather than 0.5
+	if parametersEqual(projected, map[string]interface{}(priorParams)) {
+		return prior
+	}
+	data, err = json.Marshal(projected)
+	if err != nil {
+		return prior
+	}
+	return jsontypes.NewNormalizedValue(string(data))
+}
+
+// projectParameters returns the values of received for the keys of prior, recursively for
+// objects in both.
+func projectParameters(received, prior map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for k, v := range prior {
+		r, ok := received[k]
+		if !ok {
+			continue
+		}
+		priorObject, priorIsObject := v.(map[string]interface{})
+		receivedObject, receivedIsObject := r.(map[string]interface{})
+		if priorIsObject && receivedIsObject {
+			result[k] = projectParameters(receivedObject, priorObject)
+			continue
+		}
+		result[k] = r
+	}
+	return result
+}
+
+// parametersEqual reports whether a and b, decoded with json.Number numbers, are the same
+// JSON value. Numbers are equal when they have the same value, however they are written.
+func parametersEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			if w, ok := b[k]; !ok || !parametersEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !parametersEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		return ok && client.NumbersEqual(a.String(), b.String())
+	}
+	return a == b
+}
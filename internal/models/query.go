@@ -1,14 +1,27 @@
 package models
 
 import (
+	"context"
+	"fmt"
 	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/baselime/terraform-provider-baselime/internal/filterexpr"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"regexp"
+	"strconv"
+	"time"
 )
 
 type QueryFilter struct {
 	Key       types.String `json:"key" tfsdk:"key"`
 	Operation types.String `json:"operation" tfsdk:"operation"`
 	Value     types.String `json:"value" tfsdk:"value"`
+	Values    types.List   `json:"values" tfsdk:"values"`
 	Type      types.String `json:"type" tfsdk:"type"`
 }
 
@@ -25,37 +38,206 @@ var (
 	FilterCombinationOr  FilterCombination = "OR"
 )
 
+// Values accepted by the API in the parameters of a query.
+var (
+	QueryFilterOperations = []string{
+		"=", "!=", ">", ">=", "<", "<=",
+		"INCLUDES", "DOES_NOT_INCLUDE", "STARTS_WITH", "MATCH_REGEX",
+		"EXISTS", "DOES_NOT_EXIST", "IN", "NOT_IN",
+	}
+	QueryValueTypes = []string{"string", "number", "boolean"}
+	QueryOrders     = []string{"ASC", "DESC"}
+)
+
+// Bounds of the number of results returned by a query.
+const (
+	QueryMinLimit = 1
+	QueryMaxLimit = 1000
+)
+
+// QueryHavingOperations are the comparisons of having clauses.
+var QueryHavingOperations = []string{">", ">=", "<", "<=", "=", "!="}
+
+// QueryDurationPattern matches the durations of the time range and the granularity of
+// queries, such as 15m or 7d.
+var QueryDurationPattern = regexp.MustCompile(`^([0-9]+)([smhd])$`)
+
+// ParseQueryDuration returns the length of s, a duration matching QueryDurationPattern.
+func ParseQueryDuration(s string) (time.Duration, error) {
+	m := QueryDurationPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("invalid duration %q, expected a number followed by s, m, h or d", s)
+	}
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	unit := map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour, "d": 24 * time.Hour}[m[2]]
+	return time.Duration(n) * unit, nil
+}
+
+type QueryTimeRange struct {
+	From types.String `json:"from" tfsdk:"from"`
+	To   types.String `json:"to" tfsdk:"to"`
+}
+
+// QueryTimeRangeAttrTypes are the attribute types of a time range.
+var QueryTimeRangeAttrTypes = map[string]attr.Type{
+	"from": types.StringType,
+	"to":   types.StringType,
+}
+
+func (tr *QueryTimeRange) ToApiModel() *client.QueryTimeRange {
+	if tr == nil {
+		return nil
+	}
+	return &client.QueryTimeRange{
+		From: tr.From.ValueString(),
+		To:   tr.To.ValueString(),
+	}
+}
+
+type QueryHaving struct {
+	Key       types.String  `json:"key" tfsdk:"key"`
+	Operation types.String  `json:"operation" tfsdk:"operation"`
+	Value     types.Float64 `json:"value" tfsdk:"value"`
+}
+
+// QueryHavingAttrTypes are the attribute types of a having clause.
+var QueryHavingAttrTypes = map[string]attr.Type{
+	"key":       types.StringType,
+	"operation": types.StringType,
+	"value":     types.Float64Type,
+}
+
+func (h *QueryHaving) ToApiModel() client.QueryHaving {
+	return client.QueryHaving{
+		Key:       h.Key.ValueString(),
+		Operation: h.Operation.ValueString(),
+		Value:     h.Value.ValueFloat64(),
+	}
+}
+
 type QueryOrderBy struct {
 	Value types.String `json:"value" tfsdk:"value"`
 	Order types.String `json:"order" tfsdk:"order"`
 }
 
+// QueryOrderByAttrTypes are the attribute types of the order of the results.
+var QueryOrderByAttrTypes = map[string]attr.Type{
+	"value": types.StringType,
+	"order": types.StringType,
+}
+
 type QueryGroupBy struct {
 	Type  types.String `json:"type" tfsdk:"type"`
 	Value types.String `json:"value" tfsdk:"value"`
 }
 
+// QueryGroupByAttrTypes are the attribute types of a field the results are grouped by.
+var QueryGroupByAttrTypes = map[string]attr.Type{
+	"type":  types.StringType,
+	"value": types.StringType,
+}
+
 type QueryCalculation struct {
-	Key      types.String `json:"key" tfsdk:"key"`
-	Operator types.String `json:"operator" tfsdk:"operator"`
-	Alias    types.String `json:"alias" tfsdk:"alias"`
+	Key       types.String  `json:"key" tfsdk:"key"`
+	Operator  types.String  `json:"operator" tfsdk:"operator"`
+	Parameter types.Float64 `json:"parameter" tfsdk:"parameter"`
+	Alias     types.String  `json:"alias" tfsdk:"alias"`
+}
+
+// QueryCalculationAttrTypes are the attribute types of a calculation.
+var QueryCalculationAttrTypes = map[string]attr.Type{
+	"key":       types.StringType,
+	"operator":  types.StringType,
+	"parameter": types.Float64Type,
+	"alias":     types.StringType,
+}
+
+// SearchNeedleAttrTypes are the attribute types of a search needle.
+var SearchNeedleAttrTypes = map[string]attr.Type{
+	"value":      types.StringType,
+	"is_regex":   types.BoolType,
+	"match_case": types.BoolType,
 }
 
 func (c *QueryCalculation) ToApiModel() client.QueryCalculation {
-	return client.QueryCalculation{
+	calc := client.QueryCalculation{
 		Key:      c.Key.ValueString(),
 		Operator: c.Operator.ValueString(),
 		Alias:    c.Alias.ValueString(),
 	}
+	if !c.Parameter.IsNull() && !c.Parameter.IsUnknown() {
+		parameter := c.Parameter.ValueFloat64()
+		calc.Parameter = &parameter
+	}
+	return calc
 }
 
-func (qf *QueryFilter) ToApiModel() *client.QueryFilter {
+// ToApiModel converts the filter. The error says why its value is invalid, once the
+// value is known.
+func (qf *QueryFilter) ToApiModel() (*client.QueryFilter, error) {
+	value, err := QueryFilterValueToApiModel(qf.Operation.ValueString(), qf.Type.ValueString(), qf.Value.ValueString(), qf.Values)
+	if !qf.IsValueKnown() {
+		err = nil
+	}
 	return &client.QueryFilter{
 		Key:       qf.Key.ValueString(),
 		Operation: qf.Operation.ValueString(),
-		Value:     qf.Value.ValueString(),
+		Value:     value,
 		Type:      qf.Type.ValueString(),
+	}, err
+}
+
+// IsValueKnown reports whether the attributes the value of the filter depends on are
+// known.
+func (qf *QueryFilter) IsValueKnown() bool {
+	if qf.Operation.IsUnknown() || qf.Type.IsUnknown() || qf.Value.IsUnknown() || qf.Values.IsUnknown() {
+		return false
 	}
+	for _, v := range qf.Values.Elements() {
+		if v.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
+
+// QueryFiltersToApiModel converts list, the filters at p. Values not known yet are left
+// out, and invalid values are reported at their path.
+func QueryFiltersToApiModel(ctx context.Context, list types.List, p path.Path, diags *diag.Diagnostics) []client.QueryFilter {
+	filters := make([]client.QueryFilter, 0, len(list.Elements()))
+	for i, elem := range list.Elements() {
+		obj, ok := elem.(types.Object)
+		if !ok || obj.IsNull() || obj.IsUnknown() {
+			continue
+		}
+		var filter QueryFilter
+		diags.Append(obj.As(ctx, &filter, basetypes.ObjectAsOptions{})...)
+		f, err := filter.ToApiModel()
+		if err != nil {
+			diags.AddAttributeError(p.AtListIndex(i), "Invalid Filter Value", err.Error())
+		}
+		filters = append(filters, *f)
+	}
+	return filters
+}
+
+// QueryFiltersFromApiModel converts the filters of a query returned by the API.
+func QueryFiltersFromApiModel(filters []client.QueryFilter) types.List {
+	elems := make([]attr.Value, 0, len(filters))
+	for _, f := range filters {
+		value, values := queryFilterValueFromApiModel(f.Value)
+		elems = append(elems, types.ObjectValueMust(QueryFilterAttrTypes, map[string]attr.Value{
+			"key":       types.StringValue(f.Key),
+			"operation": types.StringValue(f.Operation),
+			"value":     value,
+			"values":    values,
+			"type":      types.StringValue(f.Type),
+		}))
+	}
+	return types.ListValueMust(types.ObjectType{AttrTypes: QueryFilterAttrTypes}, elems)
 }
 
 func (qgb *QueryGroupBy) ToApiModel() *client.QueryGroupBy {
@@ -66,6 +248,9 @@ func (qgb *QueryGroupBy) ToApiModel() *client.QueryGroupBy {
 }
 
 func (qob *QueryOrderBy) ToApiModel() *client.QueryOrderBy {
+	if qob == nil {
+		return nil
+	}
 	return &client.QueryOrderBy{
 		Value: qob.Value.ValueString(),
 		Order: qob.Order.ValueString(),
@@ -73,6 +258,9 @@ func (qob *QueryOrderBy) ToApiModel() *client.QueryOrderBy {
 }
 
 func (sn *SearchNeedle) ToApiModel() *client.SearchNeedle {
+	if sn == nil {
+		return nil
+	}
 	return &client.SearchNeedle{
 		Value:     sn.Value.ValueString(),
 		IsRegex:   sn.IsRegex.ValueBool(),
@@ -82,107 +270,251 @@ func (sn *SearchNeedle) ToApiModel() *client.SearchNeedle {
 
 // QueryResourceModel describes the resource data model.
 type QueryResourceModel struct {
-	Name              types.String       `tfsdk:"name"`
-	Description       types.String       `tfsdk:"description"`
-	Datasets          []string           `tfsdk:"datasets"`
-	Filters           []QueryFilter      `tfsdk:"filters"`
-	FilterCombination types.String       `tfsdk:"filter_combination"`
-	Calculations      []QueryCalculation `tfsdk:"calculations"`
-	GroupBy           []QueryGroupBy     `tfsdk:"group_by"`
-	OrderBy           *QueryOrderBy      `tfsdk:"order_by"`
-	Limit             types.Int64        `tfsdk:"limit"`
-	Needle            *SearchNeedle      `tfsdk:"needle"`
-}
-
-func (data *QueryResourceModel) FromApiObject(obj *client.Query) {
+	Name              types.String         `tfsdk:"name"`
+	Description       types.String         `tfsdk:"description"`
+	Datasets          types.List           `tfsdk:"datasets"`
+	Filters           types.List           `tfsdk:"filters"`
+	Where             types.String         `tfsdk:"where"`
+	FilterCombination types.String         `tfsdk:"filter_combination"`
+	FilterGroups      types.List           `tfsdk:"filter_group"`
+	Calculations      types.List           `tfsdk:"calculations"`
+	GroupBy           types.List           `tfsdk:"group_by"`
+	OrderBy           types.Object         `tfsdk:"order_by"`
+	Limit             types.Int64          `tfsdk:"limit"`
+	Needle            types.Object         `tfsdk:"needle"`
+	TimeRange         types.Object         `tfsdk:"time_range"`
+	Granularity       types.String         `tfsdk:"granularity"`
+	Having            types.List           `tfsdk:"having"`
+	ParametersJSON    jsontypes.Normalized `tfsdk:"parameters_json"`
+	Id                types.String         `tfsdk:"id"`
+	CreatedAt         types.String         `tfsdk:"created_at"`
+	UpdatedAt         types.String         `tfsdk:"updated_at"`
+	CreatedBy         types.String         `tfsdk:"created_by"`
+	URL               types.String         `tfsdk:"url"`
+	Timeouts          timeouts.Value       `tfsdk:"timeouts"`
+}
+
+// NewQueryResourceModel returns a query whose attributes are all null, as when none of
+// them is set.
+func NewQueryResourceModel() QueryResourceModel {
+	return QueryResourceModel{
+		Datasets:       types.ListNull(types.StringType),
+		Filters:        types.ListNull(types.ObjectType{AttrTypes: QueryFilterAttrTypes}),
+		FilterGroups:   types.ListNull(types.ObjectType{AttrTypes: FilterGroupAttrTypes(1)}),
+		Calculations:   types.ListNull(types.ObjectType{AttrTypes: QueryCalculationAttrTypes}),
+		GroupBy:        types.ListNull(types.ObjectType{AttrTypes: QueryGroupByAttrTypes}),
+		OrderBy:        types.ObjectNull(QueryOrderByAttrTypes),
+		Needle:         types.ObjectNull(SearchNeedleAttrTypes),
+		TimeRange:      types.ObjectNull(QueryTimeRangeAttrTypes),
+		Having:         types.ListNull(types.ObjectType{AttrTypes: QueryHavingAttrTypes}),
+		ParametersJSON: jsontypes.NewNormalizedNull(),
+	}
+}
+
+func (data *QueryResourceModel) FromApiObject(ctx context.Context, obj *client.Query) diag.Diagnostics {
+	var diags diag.Diagnostics
+	checkFilterGroupDepth(obj.Parameters.FilterGroups, &diags)
+	diags.Append(data.fromApiObject(ctx, obj)...)
+	return diags
+}
+
+// fromApiObject sets the attributes of obj, leaving out filter groups nested deeper
+// than MaxFilterGroupDepth.
+func (data *QueryResourceModel) fromApiObject(ctx context.Context, obj *client.Query) diag.Diagnostics {
+	var diags diag.Diagnostics
+	data.FromApiMetadata(obj)
 	data.Name = types.StringValue(obj.Id)
 	data.Description = types.StringValue(obj.Description)
-	data.Datasets = obj.Parameters.Datasets
-	if obj.Parameters.Filters != nil {
-		data.Filters = func() []QueryFilter {
-			filters := make([]QueryFilter, 0)
-			for _, f := range obj.Parameters.Filters {
-				filters = append(filters, QueryFilter{
-					Key:       types.StringValue(f.Key),
-					Operation: types.StringValue(f.Operation),
-					Value:     types.StringValue(f.Value),
-					Type:      types.StringValue(f.Type),
-				})
-			}
-			return filters
-		}()
-	}
+	data.Datasets = stringList(obj.Parameters.Datasets)
+	// numbers the API returns in another form keep the one they were written in. The
+	// values of the state only serve to compare them, so values written before they were
+	// checked do not stop the refresh.
+	prior, _ := data.ToApiObject(ctx)
+	// fields the API leaves out were removed outside Terraform, unless the state has none
+	data.Filters = listKeepingNull(QueryFiltersFromApiModel(withPriorFilterValues(obj.Parameters.Filters, prior.Parameters.Filters)), data.Filters)
 	data.FilterCombination = types.StringValue(string(obj.Parameters.FilterCombination))
-	data.Calculations = func() []QueryCalculation {
-		cals := make([]QueryCalculation, 0)
-		for _, c := range obj.Parameters.Calculations {
-			cals = append(cals, QueryCalculation{
-				Key:      types.StringValue(c.Key),
-				Operator: types.StringValue(c.Operator),
-				Alias:    types.StringValue(c.Alias),
-			})
-		}
-		return cals
-	}()
-	if obj.Parameters.GroupBy != nil {
-		data.GroupBy = func() []QueryGroupBy {
-			groups := make([]QueryGroupBy, 0)
-			for _, g := range obj.Parameters.GroupBy {
-				groups = append(groups, QueryGroupBy{
-					Type:  types.StringValue(g.Type),
-					Value: types.StringValue(g.Value),
-				})
-			}
-			return groups
-		}()
-	}
-	if obj.Parameters.OrderBy != nil {
-		data.OrderBy = &QueryOrderBy{
-			Value: types.StringValue(obj.Parameters.OrderBy.Value),
-			Order: types.StringValue(obj.Parameters.OrderBy.Order),
+	// state written before filter groups existed has none rather than an empty list
+	data.FilterGroups = listKeepingNull(FilterGroupsFromApiModel(withPriorFilterGroupValues(obj.Parameters.FilterGroups, prior.Parameters.FilterGroups), 1), data.FilterGroups)
+	// a filter expression is kept as written while it still describes the filters
+	if !data.Where.IsNull() && !filterexpr.Equivalent(data.Where.ValueString(), obj.Parameters.Filters, obj.Parameters.FilterCombination) {
+		data.Where = types.StringValue(filterexpr.Format(obj.Parameters.Filters, obj.Parameters.FilterCombination))
+	}
+	cals := make([]QueryCalculation, 0, len(obj.Parameters.Calculations))
+	for _, c := range obj.Parameters.Calculations {
+		cals = append(cals, QueryCalculation{
+			Key:       types.StringValue(c.Key),
+			Operator:  types.StringValue(c.Operator),
+			Parameter: types.Float64PointerValue(c.Parameter),
+			Alias:     types.StringValue(c.Alias),
+		})
+	}
+	data.Calculations = listKeepingNull(objectList(ctx, QueryCalculationAttrTypes, cals, &diags), data.Calculations)
+	groups := make([]QueryGroupBy, 0, len(obj.Parameters.GroupBy))
+	for _, g := range obj.Parameters.GroupBy {
+		groups = append(groups, QueryGroupBy{
+			Type:  types.StringValue(g.Type),
+			Value: types.StringValue(g.Value),
+		})
+	}
+	data.GroupBy = listKeepingNull(objectList(ctx, QueryGroupByAttrTypes, groups, &diags), data.GroupBy)
+	var orderBy *QueryOrderBy
+	if ob := obj.Parameters.OrderBy; ob != nil {
+		orderBy = &QueryOrderBy{
+			Value: types.StringValue(ob.Value),
+			Order: types.StringValue(ob.Order),
 		}
 	}
+	data.OrderBy = objectValue(ctx, QueryOrderByAttrTypes, orderBy, &diags)
 	data.Limit = types.Int64Value(obj.Parameters.Limit)
-	if obj.Parameters.Needle != nil {
-		data.Needle = &SearchNeedle{
-			Value:     types.StringValue(obj.Parameters.Needle.Value),
-			IsRegex:   types.BoolValue(obj.Parameters.Needle.IsRegex),
-			MatchCase: types.BoolValue(obj.Parameters.Needle.MatchCase),
+	var needle *SearchNeedle
+	if n := obj.Parameters.Needle; n != nil {
+		needle = &SearchNeedle{
+			Value:     types.StringValue(n.Value),
+			IsRegex:   types.BoolValue(n.IsRegex),
+			MatchCase: types.BoolValue(n.MatchCase),
 		}
 	}
+	data.Needle = objectValue(ctx, SearchNeedleAttrTypes, needle, &diags)
+	var timeRange *QueryTimeRange
+	if tr := obj.Parameters.TimeRange; tr != nil {
+		timeRange = &QueryTimeRange{
+			From: types.StringValue(tr.From),
+			To:   stringOrNull(tr.To),
+		}
+	}
+	data.TimeRange = objectValue(ctx, QueryTimeRangeAttrTypes, timeRange, &diags)
+	data.Granularity = stringOrNull(obj.Parameters.Granularity)
+	// a query without having clauses keeps the empty list it may have been written with
+	having := make([]QueryHaving, 0, len(obj.Parameters.Having))
+	for _, h := range obj.Parameters.Having {
+		having = append(having, QueryHaving{
+			Key:       types.StringValue(h.Key),
+			Operation: types.StringValue(h.Operation),
+			Value:     types.Float64Value(h.Value),
+		})
+	}
+	data.Having = listKeepingNull(objectList(ctx, QueryHavingAttrTypes, having, &diags), data.Having)
+	data.ParametersJSON = ParametersJSONFromApiModel(obj.Parameters, data.ParametersJSON)
+	return diags
+}
+
+// FromApiMetadata sets the attributes computed by the API.
+func (data *QueryResourceModel) FromApiMetadata(obj *client.Query) {
+	data.Id = types.StringValue(obj.Id)
+	data.CreatedAt = stringOrNull(obj.Created)
+	data.UpdatedAt = stringOrNull(obj.Updated)
+	data.CreatedBy = stringOrNull(obj.UserId)
+	data.URL = stringOrNull(obj.ConsoleURL())
 }
 
-func (data *QueryResourceModel) ToApiObject() *client.Query {
+// ToApiObject converts the query. Values not known yet are left out.
+func (data *QueryResourceModel) ToApiObject(ctx context.Context) (*client.Query, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var cals []QueryCalculation
+	objectsAs(ctx, data.Calculations, &cals, &diags)
+	var groupBy []QueryGroupBy
+	objectsAs(ctx, data.GroupBy, &groupBy, &diags)
+	var having []QueryHaving
+	objectsAs(ctx, data.Having, &having, &diags)
+	var orderBy *QueryOrderBy
+	if o := new(QueryOrderBy); objectAs(ctx, data.OrderBy, o, &diags) {
+		orderBy = o
+	}
+	var needle *SearchNeedle
+	if n := new(SearchNeedle); objectAs(ctx, data.Needle, n, &diags) {
+		needle = n
+	}
+	var timeRange *QueryTimeRange
+	if tr := new(QueryTimeRange); objectAs(ctx, data.TimeRange, tr, &diags) {
+		timeRange = tr
+	}
+
+	params := client.QueryParameters{
+		Datasets:          stringsOf(data.Datasets),
+		Filters:           QueryFiltersToApiModel(ctx, data.Filters, path.Root("filters"), &diags),
+		FilterCombination: data.FilterCombination.ValueString(),
+		FilterGroups:      FilterGroupsToApiModel(ctx, data.FilterGroups, path.Root("filter_group"), &diags),
+		Calculations:      make([]client.QueryCalculation, 0, len(cals)),
+		GroupBy:           make([]client.QueryGroupBy, 0, len(groupBy)),
+		OrderBy:           orderBy.ToApiModel(),
+		Limit:             data.Limit.ValueInt64(),
+		Needle:            needle.ToApiModel(),
+		TimeRange:         timeRange.ToApiModel(),
+		Granularity:       data.Granularity.ValueString(),
+		Having:            make([]client.QueryHaving, 0, len(having)),
+		Extra:             ParametersJSONToApiModel(data.ParametersJSON),
+	}
+	for _, c := range cals {
+		params.Calculations = append(params.Calculations, c.ToApiModel())
+	}
+	for _, g := range groupBy {
+		params.GroupBy = append(params.GroupBy, *g.ToApiModel())
+	}
+	for _, h := range having {
+		params.Having = append(params.Having, h.ToApiModel())
+	}
 	return &client.Query{
 		Id:          data.Name.ValueString(),
 		Description: data.Description.ValueString(),
-		Parameters: client.QueryParameters{
-			Datasets: data.Datasets,
-			Filters: func() []client.QueryFilter {
-				filters := make([]client.QueryFilter, 0, len(data.Filters))
-				for _, f := range data.Filters {
-					filters = append(filters, *f.ToApiModel())
-				}
-				return filters
-			}(),
-			FilterCombination: data.FilterCombination.ValueString(),
-			Calculations: func() []client.QueryCalculation {
-				cals := make([]client.QueryCalculation, 0)
-				for _, c := range data.Calculations {
-					cals = append(cals, c.ToApiModel())
-				}
-				return cals
-			}(),
-			GroupBy: func() []client.QueryGroupBy {
-				groups := make([]client.QueryGroupBy, 0)
-				for _, g := range data.GroupBy {
-					groups = append(groups, *g.ToApiModel())
-				}
-				return groups
-			}(),
-			OrderBy: data.OrderBy.ToApiModel(),
-			Limit:   data.Limit.ValueInt64(),
-			Needle:  data.Needle.ToApiModel(),
-		},
-	}
+		Parameters:  params,
+	}, diags
+}
+
+// QueryDataSourceModel describes the data source data model.
+type QueryDataSourceModel struct {
+	Name              types.String `tfsdk:"name"`
+	Description       types.String `tfsdk:"description"`
+	Datasets          types.List   `tfsdk:"datasets"`
+	Filters           types.List   `tfsdk:"filters"`
+	FilterCombination types.String `tfsdk:"filter_combination"`
+	FilterGroups      types.List   `tfsdk:"filter_group"`
+	Calculations      types.List   `tfsdk:"calculations"`
+	GroupBy           types.List   `tfsdk:"group_by"`
+	OrderBy           types.Object `tfsdk:"order_by"`
+	Limit             types.Int64  `tfsdk:"limit"`
+	Needle            types.Object `tfsdk:"needle"`
+	TimeRange         types.Object `tfsdk:"time_range"`
+	Granularity       types.String `tfsdk:"granularity"`
+	Having            types.List   `tfsdk:"having"`
+	Id                types.String `tfsdk:"id"`
+	CreatedAt         types.String `tfsdk:"created_at"`
+	UpdatedAt         types.String `tfsdk:"updated_at"`
+	CreatedBy         types.String `tfsdk:"created_by"`
+	URL               types.String `tfsdk:"url"`
+}
+
+func (data *QueryDataSourceModel) FromApiObject(ctx context.Context, obj *client.Query) diag.Diagnostics {
+	var diags diag.Diagnostics
+	warnFilterGroupDepth(obj.Id, obj.Parameters.FilterGroups, &diags)
+	m := NewQueryResourceModel()
+	diags.Append(m.fromApiObject(ctx, obj)...)
+	data.Name = m.Name
+	data.Description = m.Description
+	data.Datasets = m.Datasets
+	data.Filters = m.Filters
+	data.FilterCombination = m.FilterCombination
+	data.FilterGroups = FilterGroupsFromApiModel(obj.Parameters.FilterGroups, 1)
+	data.Calculations = m.Calculations
+	data.GroupBy = m.GroupBy
+	data.OrderBy = m.OrderBy
+	data.Limit = m.Limit
+	data.Needle = m.Needle
+	data.TimeRange = m.TimeRange
+	data.Granularity = m.Granularity
+	data.Having = m.Having
+	data.Id = m.Id
+	data.CreatedAt = m.CreatedAt
+	data.UpdatedAt = m.UpdatedAt
+	data.CreatedBy = m.CreatedBy
+	data.URL = m.URL
+	return diags
+}
+
+// QueriesDataSourceModel describes the data source data model.
+type QueriesDataSourceModel struct {
+	NameRegex           types.String           `tfsdk:"name_regex"`
+	DescriptionContains types.String           `tfsdk:"description_contains"`
+	Dataset             types.String           `tfsdk:"dataset"`
+	Names               []string               `tfsdk:"names"`
+	Queries             []QueryDataSourceModel `tfsdk:"queries"`
 }
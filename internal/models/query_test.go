@@ -0,0 +1,410 @@
+package models
+
+import (
+	"context"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"testing"
+)
+
+func testQueryCalculation(operator, alias string) attr.Value {
+	return types.ObjectValueMust(QueryCalculationAttrTypes, map[string]attr.Value{
+		"key":       types.StringValue(""),
+		"operator":  types.StringValue(operator),
+		"parameter": types.Float64Null(),
+		"alias":     types.StringValue(alias),
+	})
+}
+
+func testQueryModel() QueryResourceModel {
+	m := NewQueryResourceModel()
+	m.Name = types.StringValue("api-errors")
+	m.Description = types.StringValue("Errors of the api")
+	m.Datasets = stringList([]string{"lambda-logs"})
+	m.Filters = QueryFiltersFromApiModel([]client.QueryFilter{{Key: "level", Operation: "=", Value: filterValue("error"), Type: "string"}})
+	m.FilterCombination = types.StringValue("AND")
+	m.Calculations = types.ListValueMust(types.ObjectType{AttrTypes: QueryCalculationAttrTypes}, []attr.Value{
+		testQueryCalculation("COUNT", "count"),
+	})
+	m.GroupBy = types.ListValueMust(types.ObjectType{AttrTypes: QueryGroupByAttrTypes}, []attr.Value{
+		types.ObjectValueMust(QueryGroupByAttrTypes, map[string]attr.Value{
+			"type":  types.StringValue("string"),
+			"value": types.StringValue("service"),
+		}),
+	})
+	m.OrderBy = types.ObjectValueMust(QueryOrderByAttrTypes, map[string]attr.Value{
+		"value": types.StringValue("count"),
+		"order": types.StringValue("DESC"),
+	})
+	m.Limit = types.Int64Value(10)
+	m.Needle = types.ObjectValueMust(SearchNeedleAttrTypes, map[string]attr.Value{
+		"value":      types.StringValue("timeout"),
+		"is_regex":   types.BoolValue(false),
+		"match_case": types.BoolValue(true),
+	})
+	m.TimeRange = types.ObjectValueMust(QueryTimeRangeAttrTypes, map[string]attr.Value{
+		"from": types.StringValue("1h"),
+		"to":   types.StringNull(),
+	})
+	return m
+}
+
+func filterValue(s string) *client.QueryFilterValue {
+	v := client.StringFilterValue(s)
+	return &v
+}
+
+func testQuery() *client.Query {
+	return &client.Query{
+		Id:          "api-errors",
+		Description: "Errors of the api",
+		Parameters: client.QueryParameters{
+			Datasets:          []string{"lambda-logs"},
+			Filters:           []client.QueryFilter{{Key: "level", Operation: "=", Value: filterValue("error"), Type: "string"}},
+			FilterCombination: "AND",
+			FilterGroups:      []client.QueryFilterGroup{},
+			Calculations:      []client.QueryCalculation{{Operator: "COUNT", Alias: "count"}},
+			GroupBy:           []client.QueryGroupBy{{Type: "string", Value: "service"}},
+			OrderBy:           &client.QueryOrderBy{Value: "count", Order: "DESC"},
+			Limit:             10,
+			Needle:            &client.SearchNeedle{Value: "timeout", MatchCase: true},
+			TimeRange:         &client.QueryTimeRange{From: "1h"},
+			Having:            []client.QueryHaving{},
+		},
+	}
+}
+
+func TestQueryResourceModel_ToApiObject(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(m *QueryResourceModel)
+		want   func(p *client.QueryParameters)
+	}{
+		{
+			name:   "known",
+			modify: func(m *QueryResourceModel) {},
+			want:   func(p *client.QueryParameters) {},
+		},
+		{
+			name:   "unknown datasets",
+			modify: func(m *QueryResourceModel) { m.Datasets = types.ListUnknown(types.StringType) },
+			want:   func(p *client.QueryParameters) { p.Datasets = nil },
+		},
+		{
+			name: "unknown dataset",
+			modify: func(m *QueryResourceModel) {
+				m.Datasets = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("lambda-logs"), types.StringUnknown()})
+			},
+			want: func(p *client.QueryParameters) {},
+		},
+		{
+			name: "unknown filters",
+			modify: func(m *QueryResourceModel) {
+				m.Filters = types.ListUnknown(types.ObjectType{AttrTypes: QueryFilterAttrTypes})
+			},
+			want: func(p *client.QueryParameters) { p.Filters = []client.QueryFilter{} },
+		},
+		{
+			name: "unknown filter",
+			modify: func(m *QueryResourceModel) {
+				m.Filters = types.ListValueMust(types.ObjectType{AttrTypes: QueryFilterAttrTypes}, []attr.Value{
+					types.ObjectUnknown(QueryFilterAttrTypes),
+				})
+			},
+			want: func(p *client.QueryParameters) { p.Filters = []client.QueryFilter{} },
+		},
+		{
+			name: "unknown calculation",
+			modify: func(m *QueryResourceModel) {
+				m.Calculations = types.ListValueMust(types.ObjectType{AttrTypes: QueryCalculationAttrTypes}, []attr.Value{
+					testQueryCalculation("COUNT", "count"),
+					types.ObjectUnknown(QueryCalculationAttrTypes),
+				})
+			},
+			want: func(p *client.QueryParameters) {},
+		},
+		{
+			name: "unknown alias",
+			modify: func(m *QueryResourceModel) {
+				m.Calculations = types.ListValueMust(types.ObjectType{AttrTypes: QueryCalculationAttrTypes}, []attr.Value{
+					types.ObjectValueMust(QueryCalculationAttrTypes, map[string]attr.Value{
+						"key":       types.StringValue(""),
+						"operator":  types.StringValue("COUNT"),
+						"parameter": types.Float64Unknown(),
+						"alias":     types.StringUnknown(),
+					}),
+				})
+			},
+			want: func(p *client.QueryParameters) { p.Calculations[0].Alias = "" },
+		},
+		{
+			name: "unknown group by",
+			modify: func(m *QueryResourceModel) {
+				m.GroupBy = types.ListUnknown(types.ObjectType{AttrTypes: QueryGroupByAttrTypes})
+			},
+			want: func(p *client.QueryParameters) { p.GroupBy = []client.QueryGroupBy{} },
+		},
+		{
+			name:   "unknown order by",
+			modify: func(m *QueryResourceModel) { m.OrderBy = types.ObjectUnknown(QueryOrderByAttrTypes) },
+			want:   func(p *client.QueryParameters) { p.OrderBy = nil },
+		},
+		{
+			name:   "unknown limit",
+			modify: func(m *QueryResourceModel) { m.Limit = types.Int64Unknown() },
+			want:   func(p *client.QueryParameters) { p.Limit = 0 },
+		},
+		{
+			name:   "unknown needle",
+			modify: func(m *QueryResourceModel) { m.Needle = types.ObjectUnknown(SearchNeedleAttrTypes) },
+			want:   func(p *client.QueryParameters) { p.Needle = nil },
+		},
+		{
+			name:   "unknown time range",
+			modify: func(m *QueryResourceModel) { m.TimeRange = types.ObjectUnknown(QueryTimeRangeAttrTypes) },
+			want:   func(p *client.QueryParameters) { p.TimeRange = nil },
+		},
+		{
+			name: "unknown having",
+			modify: func(m *QueryResourceModel) {
+				m.Having = types.ListUnknown(types.ObjectType{AttrTypes: QueryHavingAttrTypes})
+			},
+			want: func(p *client.QueryParameters) {},
+		},
+		{
+			name: "unknown filter groups",
+			modify: func(m *QueryResourceModel) {
+				m.FilterGroups = types.ListUnknown(types.ObjectType{AttrTypes: FilterGroupAttrTypes(1)})
+			},
+			want: func(p *client.QueryParameters) {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testQueryModel()
+			tt.modify(&m)
+			want := testQuery()
+			tt.want(&want.Parameters)
+			got, diags := m.ToApiObject(context.Background())
+			if diags.HasError() {
+				t.Fatalf("ToApiObject() diagnostics: %v", diags)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ToApiObject() = %+v, want %+v", got.Parameters, want.Parameters)
+			}
+		})
+	}
+}
+
+func TestQueryResourceModel_FromApiObject(t *testing.T) {
+	calculationsType := types.ObjectType{AttrTypes: QueryCalculationAttrTypes}
+	groupByType := types.ObjectType{AttrTypes: QueryGroupByAttrTypes}
+	havingType := types.ObjectType{AttrTypes: QueryHavingAttrTypes}
+	tests := []struct {
+		name  string
+		prior func(m *QueryResourceModel)
+		api   func(p *client.QueryParameters)
+		want  func(m *QueryResourceModel)
+	}{
+		{
+			name:  "import",
+			prior: func(m *QueryResourceModel) {},
+			api:   func(p *client.QueryParameters) {},
+			want:  func(m *QueryResourceModel) {},
+		},
+		{
+			name:  "null calculations",
+			prior: func(m *QueryResourceModel) { m.Calculations = types.ListNull(calculationsType) },
+			api:   func(p *client.QueryParameters) { p.Calculations = []client.QueryCalculation{} },
+			want:  func(m *QueryResourceModel) { m.Calculations = types.ListNull(calculationsType) },
+		},
+		{
+			name:  "empty calculations",
+			prior: func(m *QueryResourceModel) { m.Calculations = types.ListValueMust(calculationsType, []attr.Value{}) },
+			api:   func(p *client.QueryParameters) { p.Calculations = nil },
+			want:  func(m *QueryResourceModel) { m.Calculations = types.ListValueMust(calculationsType, []attr.Value{}) },
+		},
+		{
+			name:  "null group by",
+			prior: func(m *QueryResourceModel) { m.GroupBy = types.ListNull(groupByType) },
+			api:   func(p *client.QueryParameters) { p.GroupBy = []client.QueryGroupBy{} },
+			want:  func(m *QueryResourceModel) { m.GroupBy = types.ListNull(groupByType) },
+		},
+		{
+			name:  "empty group by",
+			prior: func(m *QueryResourceModel) { m.GroupBy = types.ListValueMust(groupByType, []attr.Value{}) },
+			api:   func(p *client.QueryParameters) { p.GroupBy = []client.QueryGroupBy{} },
+			want:  func(m *QueryResourceModel) { m.GroupBy = types.ListValueMust(groupByType, []attr.Value{}) },
+		},
+		{
+			name:  "empty having",
+			prior: func(m *QueryResourceModel) { m.Having = types.ListValueMust(havingType, []attr.Value{}) },
+			api:   func(p *client.QueryParameters) { p.Having = nil },
+			want:  func(m *QueryResourceModel) { m.Having = types.ListValueMust(havingType, []attr.Value{}) },
+		},
+		{
+			name:  "null filters",
+			prior: func(m *QueryResourceModel) {},
+			api:   func(p *client.QueryParameters) { p.Filters = []client.QueryFilter{} },
+			want: func(m *QueryResourceModel) {
+				m.Filters = types.ListNull(types.ObjectType{AttrTypes: QueryFilterAttrTypes})
+			},
+		},
+		{
+			name:  "empty filter groups",
+			prior: func(m *QueryResourceModel) { m.FilterGroups = FilterGroupsFromApiModel(nil, 1) },
+			api:   func(p *client.QueryParameters) { p.FilterGroups = nil },
+			want:  func(m *QueryResourceModel) { m.FilterGroups = FilterGroupsFromApiModel(nil, 1) },
+		},
+		{
+			name:  "calculations added outside Terraform",
+			prior: func(m *QueryResourceModel) { m.Calculations = types.ListNull(calculationsType) },
+			api:   func(p *client.QueryParameters) {},
+			want:  func(m *QueryResourceModel) {},
+		},
+		{
+			name:  "filters removed outside Terraform",
+			prior: func(m *QueryResourceModel) { m.Filters = testQueryModel().Filters },
+			api:   func(p *client.QueryParameters) { p.Filters = nil },
+			want:  func(m *QueryResourceModel) { m.Filters = QueryFiltersFromApiModel(nil) },
+		},
+		{
+			name:  "group by removed outside Terraform",
+			prior: func(m *QueryResourceModel) { m.GroupBy = testQueryModel().GroupBy },
+			api:   func(p *client.QueryParameters) { p.GroupBy = nil },
+			want:  func(m *QueryResourceModel) { m.GroupBy = types.ListValueMust(groupByType, []attr.Value{}) },
+		},
+		{
+			name:  "order by removed outside Terraform",
+			prior: func(m *QueryResourceModel) { m.OrderBy = testQueryModel().OrderBy },
+			api:   func(p *client.QueryParameters) { p.OrderBy = nil },
+			want:  func(m *QueryResourceModel) { m.OrderBy = types.ObjectNull(QueryOrderByAttrTypes) },
+		},
+		{
+			name:  "needle removed outside Terraform",
+			prior: func(m *QueryResourceModel) { m.Needle = testQueryModel().Needle },
+			api:   func(p *client.QueryParameters) { p.Needle = nil },
+			want:  func(m *QueryResourceModel) { m.Needle = types.ObjectNull(SearchNeedleAttrTypes) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewQueryResourceModel()
+			tt.prior(&m)
+			query := testQuery()
+			tt.api(&query.Parameters)
+			if diags := m.FromApiObject(context.Background(), query); diags.HasError() {
+				t.Fatalf("FromApiObject() diagnostics: %v", diags)
+			}
+			want := testQueryModel()
+			want.Id = types.StringValue("api-errors")
+			want.URL = m.URL
+			tt.want(&want)
+			if !reflect.DeepEqual(m, want) {
+				t.Errorf("FromApiObject() = %+v, want %+v", m, want)
+			}
+		})
+	}
+}
+
+func TestQueryResourceModel_FromApiObject_filterGroupDepth(t *testing.T) {
+	group := func(groups ...client.QueryFilterGroup) client.QueryFilterGroup {
+		return client.QueryFilterGroup{
+			FilterCombination: "AND",
+			Filters:           []client.QueryFilter{{Key: "level", Operation: "=", Value: filterValue("error"), Type: "string"}},
+			FilterGroups:      groups,
+		}
+	}
+	tests := []struct {
+		name    string
+		groups  []client.QueryFilterGroup
+		wantErr bool
+	}{
+		{name: "3 levels", groups: []client.QueryFilterGroup{group(group(group()))}},
+		{name: "4 levels", groups: []client.QueryFilterGroup{group(), group(group(group(group())))}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := testQuery()
+			query.Parameters.FilterGroups = tt.groups
+			m := NewQueryResourceModel()
+			if diags := m.FromApiObject(context.Background(), query); diags.HasError() != tt.wantErr {
+				t.Errorf("QueryResourceModel.FromApiObject() diagnostics = %v, want error %v", diags, tt.wantErr)
+			}
+			// data sources read the groups they can hold, and warn about the others
+			var d QueryDataSourceModel
+			if diags := d.FromApiObject(context.Background(), query); diags.HasError() || (diags.WarningsCount() > 0) != tt.wantErr {
+				t.Errorf("QueryDataSourceModel.FromApiObject() diagnostics = %v, want warning %v", diags, tt.wantErr)
+			}
+			if got := len(d.FilterGroups.Elements()); got != len(tt.groups) {
+				t.Errorf("QueryDataSourceModel.FromApiObject() filter groups = %d, want %d", got, len(tt.groups))
+			}
+		})
+	}
+}
+
+func TestQueryResourceModel_ToApiObject_invalidFilterValue(t *testing.T) {
+	filter := func(value types.String) attr.Value {
+		return types.ObjectValueMust(QueryFilterAttrTypes, map[string]attr.Value{
+			"key":       types.StringValue("duration"),
+			"operation": types.StringValue(">"),
+			"value":     value,
+			"values":    types.ListNull(types.StringType),
+			"type":      types.StringValue("number"),
+		})
+	}
+	filters := func(value types.String) types.List {
+		return types.ListValueMust(types.ObjectType{AttrTypes: QueryFilterAttrTypes}, []attr.Value{filter(value)})
+	}
+	tests := []struct {
+		name     string
+		modify   func(m *QueryResourceModel)
+		wantPath path.Path
+	}{
+		{
+			name:     "filter",
+			modify:   func(m *QueryResourceModel) { m.Filters = filters(types.StringValue("slow")) },
+			wantPath: path.Root("filters").AtListIndex(0),
+		},
+		{
+			name: "filter of a group",
+			modify: func(m *QueryResourceModel) {
+				m.FilterGroups = types.ListValueMust(types.ObjectType{AttrTypes: FilterGroupAttrTypes(1)}, []attr.Value{
+					types.ObjectValueMust(FilterGroupAttrTypes(1), map[string]attr.Value{
+						"filter_combination": types.StringValue("AND"),
+						"filters":            filters(types.StringValue("slow")),
+						"filter_group":       FilterGroupsFromApiModel(nil, 2),
+					}),
+				})
+			},
+			wantPath: path.Root("filter_group").AtListIndex(0).AtName("filters").AtListIndex(0),
+		},
+		{
+			name:   "unknown value",
+			modify: func(m *QueryResourceModel) { m.Filters = filters(types.StringUnknown()) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testQueryModel()
+			tt.modify(&m)
+			_, diags := m.ToApiObject(context.Background())
+			if len(tt.wantPath.Steps()) == 0 {
+				if diags.HasError() {
+					t.Errorf("ToApiObject() diagnostics = %v, want none", diags)
+				}
+				return
+			}
+			if diags.ErrorsCount() != 1 {
+				t.Fatalf("ToApiObject() diagnostics = %v, want one error", diags)
+			}
+			if d, ok := diags.Errors()[0].(diag.DiagnosticWithPath); !ok || !d.Path().Equal(tt.wantPath) {
+				t.Errorf("ToApiObject() error = %v, want it at %s", diags.Errors()[0], tt.wantPath)
+			}
+		})
+	}
+}
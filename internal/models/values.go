@@ -0,0 +1,130 @@
+package models
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"reflect"
+)
+
+// Values of the plan can be unknown until apply, for example when they come from another
+// resource, and values of the config or the state can be null. The helpers below convert
+// them to and from API objects: unknown and null values are left out, as if they were
+// not set.
+
+// objectsAs reads the objects of list into target, a pointer to a slice of structs whose
+// fields handle unknown values. Null and unknown lists have no objects, and null and
+// unknown objects are left out.
+func objectsAs(ctx context.Context, list types.List, target interface{}, diags *diag.Diagnostics) {
+	slice := reflect.ValueOf(target).Elem()
+	slice.Set(reflect.MakeSlice(slice.Type(), 0, len(list.Elements())))
+	for _, elem := range list.Elements() {
+		obj, ok := elem.(types.Object)
+		if !ok || obj.IsNull() || obj.IsUnknown() {
+			continue
+		}
+		v := reflect.New(slice.Type().Elem())
+		diags.Append(obj.As(ctx, v.Interface(), basetypes.ObjectAsOptions{})...)
+		slice.Set(reflect.Append(slice, v.Elem()))
+	}
+}
+
+// objectAs reads obj into target, a pointer to a struct whose fields handle unknown
+// values, and reports whether obj is set. Null and unknown objects leave target as it is.
+func objectAs(ctx context.Context, obj types.Object, target interface{}, diags *diag.Diagnostics) bool {
+	if obj.IsNull() || obj.IsUnknown() {
+		return false
+	}
+	objDiags := obj.As(ctx, target, basetypes.ObjectAsOptions{})
+	diags.Append(objDiags...)
+	return !objDiags.HasError()
+}
+
+// stringsOf returns the strings of list. Null and unknown lists have none, and null and
+// unknown strings are left out.
+func stringsOf(list types.List) []string {
+	if list.IsNull() || list.IsUnknown() {
+		return nil
+	}
+	result := make([]string, 0, len(list.Elements()))
+	for _, elem := range list.Elements() {
+		s, ok := elem.(types.String)
+		if !ok || s.IsNull() || s.IsUnknown() {
+			continue
+		}
+		result = append(result, s.ValueString())
+	}
+	return result
+}
+
+// stringList returns values as a list of strings. Nil slices are null lists.
+func stringList(values []string) types.List {
+	if values == nil {
+		return types.ListNull(types.StringType)
+	}
+	elems := make([]attr.Value, len(values))
+	for i, v := range values {
+		elems[i] = types.StringValue(v)
+	}
+	return types.ListValueMust(types.StringType, elems)
+}
+
+// objectList returns elements, a slice of structs with tfsdk tags, as a list of objects
+// of attrTypes. Nil slices are null lists.
+func objectList(ctx context.Context, attrTypes map[string]attr.Type, elements interface{}, diags *diag.Diagnostics) types.List {
+	elemType := types.ObjectType{AttrTypes: attrTypes}
+	if reflect.ValueOf(elements).IsNil() {
+		return types.ListNull(elemType)
+	}
+	list, listDiags := types.ListValueFrom(ctx, elemType, elements)
+	diags.Append(listDiags...)
+	return list
+}
+
+// objectValue returns value, a pointer to a struct with tfsdk tags, as an object of
+// attrTypes. Nil pointers are null objects.
+func objectValue(ctx context.Context, attrTypes map[string]attr.Type, value interface{}, diags *diag.Diagnostics) types.Object {
+	if reflect.ValueOf(value).IsNil() {
+		return types.ObjectNull(attrTypes)
+	}
+	obj, objDiags := types.ObjectValueFrom(ctx, attrTypes, value)
+	diags.Append(objDiags...)
+	return obj
+}
+
+// The API leaves out empty optional values, so that an attribute set to null is read back
+// as an empty string or list. The helpers below convert such values to null when prior,
+// the value of the attribute in the state, is null. A nil prior, such as the attribute of
+// an object the state does not have yet, is not null.
+
+// stringKeepingNull returns s, or a null string when s is empty and prior is null.
+func stringKeepingNull(s string, prior attr.Value) types.String {
+	if s == "" && prior != nil && prior.IsNull() {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+// listKeepingNull returns list, or a null list when list is empty and prior is null.
+func listKeepingNull(list types.List, prior attr.Value) types.List {
+	if len(list.Elements()) == 0 && prior != nil && prior.IsNull() {
+		return types.ListNull(list.ElementType(context.Background()))
+	}
+	return list
+}
+
+// elementAttribute returns the attribute name of the object at index i of list, or nil
+// when list has no such object.
+func elementAttribute(list types.List, i int, name string) attr.Value {
+	elems := list.Elements()
+	if i >= len(elems) {
+		return nil
+	}
+	obj, ok := elems[i].(types.Object)
+	if !ok || obj.IsNull() || obj.IsUnknown() {
+		return nil
+	}
+	return obj.Attributes()[name]
+}
@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/baselime/terraform-provider-baselime/internal/models"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AlertDataSource{}
+
+func NewAlertDataSource() datasource.DataSource {
+	return &AlertDataSource{}
+}
+
+// AlertDataSource defines the data source implementation.
+type AlertDataSource struct {
+	client *client.Client
+}
+
+func (d *AlertDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_alert"
+}
+
+func (d *AlertDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := alertDataSourceAttributes()
+	attributes["name"] = schema.StringAttribute{
+		Required:            true,
+		MarkdownDescription: "Alert name",
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Looks up an existing alert by name.",
+		Attributes:          attributes,
+	}
+}
+
+// alertDataSourceAttributes returns the computed attributes of an alert, shared by the
+// baselime_alert and baselime_alerts data sources.
+func alertDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Alert name",
+		},
+		"id": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Alert ID, the same as its name",
+		},
+		"created_at": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Time the alert was created",
+		},
+		"updated_at": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Time the alert was last updated",
+		},
+		"created_by": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "ID of the user who created the alert",
+		},
+		"url": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Link to the alert in the Baselime console",
+		},
+		"description": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Alert description",
+		},
+		"enabled": schema.BoolAttribute{
+			Computed:            true,
+			MarkdownDescription: "Alert enabled",
+		},
+		"channels": schema.ListAttribute{
+			Computed:            true,
+			MarkdownDescription: "Alert channels",
+			ElementType: types.ObjectType{
+				AttrTypes: models.AlertChannelAttrTypes,
+			},
+		},
+		"query": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Alert query",
+		},
+		"threshold": schema.ObjectAttribute{
+			Computed:            true,
+			MarkdownDescription: "Alert threshold",
+			AttributeTypes:      models.AlertThresholdAttrTypes,
+		},
+		"frequency": schema.StringAttribute{
+			Computed: true,
+		},
+		"window": schema.StringAttribute{
+			Computed: true,
+		},
+	}
+}
+
+func (d *AlertDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*DataSourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *DataSourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = provider.Client
+}
+
+func (d *AlertDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data models.AlertDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	alert, err := d.client.GetAlert(ctx, data.Name.ValueString())
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read alert", err)
+		return
+	}
+	if alert == nil {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Alert not found",
+			fmt.Sprintf("No alert named %q exists in the workspace and environment of the API key.", data.Name.ValueString()))
+		return
+	}
+	resp.Diagnostics.Append(data.FromApiModel(ctx, alert)...)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/baselime/terraform-provider-baselime/client/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"math/big"
+	"testing"
+)
+
+func TestAccAlertDataSource(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	if err := srv.Put(fakeapi.Queries, testAccSharedQuery()); err != nil {
+		t.Fatal(err)
+	}
+	err := srv.Put(fakeapi.Alerts, &client.Alert{
+		Id:          "shared-alert",
+		Description: "Shared alert",
+		Enabled:     true,
+		Channels:    []client.AlertChannel{{Type: "email", Targets: []string{"oncall@example.com"}}},
+		Parameters: client.AlertParameters{
+			QueryId:   "shared-query",
+			Threshold: client.AlertThreshold{Operation: ">=", Value: big.NewFloat(5)},
+			Frequency: "5m",
+			Window:    "10m",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+data "baselime_alert" "test" {
+  name = "shared-alert"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.baselime_alert.test", "description", "Shared alert"),
+					resource.TestCheckResourceAttr("data.baselime_alert.test", "enabled", "true"),
+					resource.TestCheckResourceAttr("data.baselime_alert.test", "query", "shared-query"),
+					resource.TestCheckResourceAttr("data.baselime_alert.test", "threshold.operator", ">="),
+					resource.TestCheckResourceAttr("data.baselime_alert.test", "threshold.value", "5"),
+					resource.TestCheckResourceAttr("data.baselime_alert.test", "channels.0.targets.0", "oncall@example.com"),
+					resource.TestCheckResourceAttr("data.baselime_alert.test", "window", "10m"),
+				),
+			},
+		},
+	})
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"github.com/baselime/terraform-provider-baselime/client"
 	"github.com/baselime/terraform-provider-baselime/internal/models"
-	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -37,7 +39,42 @@ func (r *AlertResource) Schema(ctx context.Context, req resource.SchemaRequest,
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
 				Required:            true,
-				MarkdownDescription: "Alert name",
+				MarkdownDescription: "Alert name. Changing it replaces the alert, as the name is its ID in the Baselime API",
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceOnRename("alert"),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Alert ID, the same as its name",
+				PlanModifiers: []planmodifier.String{
+					idFromName(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Time the alert was created",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"updated_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Time the alert was last updated",
+			},
+			"created_by": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the user who created the alert",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"url": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Link to the alert in the Baselime console",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"description": schema.StringAttribute{
 				Required:            true,
@@ -51,12 +88,7 @@ func (r *AlertResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Required:            true,
 				MarkdownDescription: "Alert channels",
 				ElementType: types.ObjectType{
-					AttrTypes: map[string]attr.Type{
-						"type": types.StringType,
-						"targets": types.ListType{
-							ElemType: types.StringType,
-						},
-					},
+					AttrTypes: models.AlertChannelAttrTypes,
 				},
 			},
 			"query": schema.StringAttribute{
@@ -66,10 +98,7 @@ func (r *AlertResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			"threshold": schema.ObjectAttribute{
 				Required:            true,
 				MarkdownDescription: "Alert threshold",
-				AttributeTypes: map[string]attr.Type{
-					"operator": types.StringType,
-					"value":    types.NumberType,
-				},
+				AttributeTypes:      models.AlertThresholdAttrTypes,
 			},
 			"frequency": schema.StringAttribute{
 				Required: true,
@@ -77,6 +106,15 @@ func (r *AlertResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			"window": schema.StringAttribute{
 				Required: true,
 			},
+			"parameters_json": parametersJSONAttribute("alert", client.AlertParameters{}),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -108,11 +146,27 @@ func (r *AlertResource) Create(ctx context.Context, req resource.CreateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := r.client.CreateAlert(ctx, data.ToApiModel())
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	alert, diags := data.ToApiModel(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	err := r.client.CreateAlert(ctx, alert)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create alert, got error: %s", err))
+		addClientError(&resp.Diagnostics, "create alert", err)
 		return
 	}
+	data.FromApiMetadata(alert)
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, alert.ETag)...)
 	tflog.Trace(ctx, "created a resource")
 
 	// Save data into Terraform state
@@ -128,13 +182,34 @@ func (r *AlertResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	alert, err := r.client.GetAlert(ctx, data.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read alert, got error: %s", err))
+		addClientError(&resp.Diagnostics, "read alert", err)
 		return
 	}
-	data.FromApiModel(alert)
+	if alert == nil {
+		tflog.Warn(ctx, "alert not found, removing it from state", map[string]interface{}{
+			"name": data.Name.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	checkDrift := hasETag(ctx, req.Private)
+	resp.Diagnostics.Append(data.FromApiModel(ctx, alert)...)
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, alert.ETag)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if checkDrift && !resp.Diagnostics.HasError() {
+		addDriftWarning(&resp.Diagnostics, "alert", alert.Id, req.State.Raw, resp.State.Raw)
+	}
 }
 
 func (r *AlertResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -145,11 +220,32 @@ func (r *AlertResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	err := r.client.UpdateAlert(ctx, data.ToApiModel())
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	alert, diags := data.ToApiModel(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	alert.ETag, diags = getETag(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.UpdateAlert(ctx, alert)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update alert, got error: %s", err))
+		addClientError(&resp.Diagnostics, "update alert", err)
 		return
 	}
+	data.FromApiMetadata(alert)
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, alert.ETag)...)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -164,9 +260,18 @@ func (r *AlertResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	err := r.client.DeleteAlert(ctx, data.Name.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete alert, got error: %s", err))
+	// an object already deleted outside Terraform is gone as asked
+	if err != nil && !client.IsNotFound(err) {
+		addClientError(&resp.Diagnostics, "delete alert", err)
 		return
 	}
 }
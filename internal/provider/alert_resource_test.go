@@ -0,0 +1,194 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/client/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestAccAlertResource(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(srv, fakeapi.Alerts, "baselime_alert"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(srv) + testAccAlertConfig("5m", "email"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckExists(srv, fakeapi.Alerts, "baselime_alert.test"),
+					resource.TestCheckResourceAttr("baselime_alert.test", "name", "acc-alert"),
+					resource.TestCheckResourceAttr("baselime_alert.test", "query", "acc-query"),
+					resource.TestCheckResourceAttr("baselime_alert.test", "frequency", "5m"),
+					resource.TestCheckResourceAttr("baselime_alert.test", "threshold.operator", ">"),
+					resource.TestCheckResourceAttr("baselime_alert.test", "channels.0.type", "email"),
+					resource.TestCheckResourceAttr("baselime_alert.test", "channels.0.targets.0", "alerts@example.com"),
+					resource.TestCheckResourceAttr("baselime_alert.test", "id", "acc-alert"),
+					resource.TestCheckResourceAttr("baselime_alert.test", "url", "https://console.baselime.io/test-workspace/test-environment/alerts/acc-alert"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:                         "baselime_alert.test",
+				ImportState:                          true,
+				ImportStateId:                        "acc-alert",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+				ImportStateVerifyIgnore:              []string{"timeouts"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(srv) + testAccAlertConfig("15m", "slack"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("baselime_alert.test", "frequency", "15m"),
+					resource.TestCheckResourceAttr("baselime_alert.test", "channels.0.type", "slack"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply:             []plancheck.PlanCheck{plancheck.ExpectResourceAction("baselime_alert.test", plancheck.ResourceActionUpdate)},
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+		},
+	})
+}
+
+func TestAccAlertResource_disappears(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccAlertConfig("5m", "email"),
+				Check:  testAccCheckExists(srv, fakeapi.Alerts, "baselime_alert.test"),
+			},
+			// Deleting the alert outside Terraform plans a re-create
+			{
+				PreConfig: func() { srv.Delete(fakeapi.Alerts, "acc-alert") },
+				Config:    testAccProviderConfig(srv) + testAccAlertConfig("5m", "email"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("baselime_alert.test", plancheck.ResourceActionCreate)},
+				},
+				Check: testAccCheckExists(srv, fakeapi.Alerts, "baselime_alert.test"),
+			},
+			// Deleting the alert outside Terraform between refresh and destroy
+			{
+				Config:  testAccProviderConfig(srv) + testAccAlertConfig("5m", "email"),
+				Destroy: true,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("baselime_alert.test", plancheck.ResourceActionDestroy),
+						testAccEditBeforeApply(func() { srv.Delete(fakeapi.Alerts, "acc-alert") }),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccAlertResource_parametersJSON(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	withParametersJSON := func(parametersJSON string) string {
+		return testAccProviderConfig(srv) + strings.Replace(testAccAlertConfig("5m", "email"), `  window    = "15m"
+`, `  window    = "15m"
+  parameters_json = `+parametersJSON+"\n", 1)
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(srv, fakeapi.Alerts, "baselime_alert"),
+		Steps: []resource.TestStep{
+			{
+				Config:      withParametersJSON(`jsonencode({ threshold = { operation = "<" } })`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Conflicting Parameters.*threshold.operation`),
+			},
+			{
+				Config: withParametersJSON(`jsonencode({ threshold = { hysteresis = 2 }, muteFor = "1h" })`),
+				Check: func(*terraform.State) error {
+					a, _ := srv.Get(fakeapi.Alerts, "acc-alert")
+					params := a["parameters"].(map[string]interface{})
+					threshold := params["threshold"].(map[string]interface{})
+					if threshold["operation"] != ">" || threshold["hysteresis"] != float64(2) || params["muteFor"] != "1h" {
+						return fmt.Errorf("unexpected parameters in the API: %v", params)
+					}
+					return nil
+				},
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+		},
+	})
+}
+
+func TestAccAlertResource_unknownValues(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	// the targets, the datasets and the threshold come from a resource, so they are not
+	// known until it is applied
+	config := testAccProviderConfig(srv) + `
+resource "terraform_data" "settings" {
+  input = {
+    targets   = ["oncall@example.com"]
+    datasets  = ["lambda-logs"]
+    threshold = 25
+  }
+}
+` + strings.NewReplacer(
+		`targets = ["alerts@example.com"]`, `targets = terraform_data.settings.output.targets`,
+		`datasets    = ["lambda-logs"]`, `datasets    = terraform_data.settings.output.datasets`,
+		`value    = 10`, `value    = terraform_data.settings.output.threshold`,
+	).Replace(testAccAlertConfig("5m", "email"))
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(srv, fakeapi.Alerts, "baselime_alert"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectUnknownValue("baselime_alert.test", tfjsonpath.New("channels").AtSliceIndex(0).AtMapKey("targets")),
+						plancheck.ExpectUnknownValue("baselime_alert.test", tfjsonpath.New("threshold").AtMapKey("value")),
+						plancheck.ExpectUnknownValue("baselime_query.test", tfjsonpath.New("datasets")),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("baselime_alert.test", "channels.0.targets.0", "oncall@example.com"),
+					resource.TestCheckResourceAttr("baselime_alert.test", "threshold.value", "25"),
+					resource.TestCheckResourceAttr("baselime_query.test", "datasets.0", "lambda-logs"),
+				),
+			},
+		},
+	})
+}
+
+func testAccAlertConfig(frequency, channelType string) string {
+	return testAccQueryConfig + fmt.Sprintf(`
+resource "baselime_alert" "test" {
+  name        = "acc-alert"
+  description = "Acceptance test alert"
+  enabled     = true
+  channels = [
+    {
+      type    = %[2]q
+      targets = ["alerts@example.com"]
+    }
+  ]
+  query = baselime_query.test.name
+  threshold = {
+    operator = ">"
+    value    = 10
+  }
+  frequency = %[1]q
+  window    = "15m"
+}
+`, frequency, channelType)
+}
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/baselime/terraform-provider-baselime/internal/models"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AlertsDataSource{}
+
+func NewAlertsDataSource() datasource.DataSource {
+	return &AlertsDataSource{}
+}
+
+// AlertsDataSource defines the data source implementation.
+type AlertsDataSource struct {
+	client *client.Client
+}
+
+func (d *AlertsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_alerts"
+}
+
+func (d *AlertsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the alerts matching all of the given filters.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only include alerts whose name matches this regular expression",
+			},
+			"description_contains": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only include alerts whose description contains this string",
+			},
+			"channel_type": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only include alerts with a channel of this type, for example `slack`",
+			},
+			"channel_target": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only include alerts notifying this target. When `channel_type` is also set, the target must belong to a channel of that type",
+			},
+			"names": schema.ListAttribute{
+				Computed:            true,
+				MarkdownDescription: "Names of the matching alerts",
+				ElementType:         types.StringType,
+			},
+			"alerts": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Matching alerts",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: alertDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *AlertsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*DataSourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *DataSourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = provider.Client
+}
+
+func (d *AlertsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data models.AlertsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filter := newListFilter(data.NameRegex, data.DescriptionContains, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Names = []string{}
+	data.Alerts = []models.AlertDataSourceModel{}
+	err := d.client.ListAlerts(ctx, nil, func(a *client.Alert) bool {
+		if !filter.matches(a.Id, a.Description) || !hasChannel(a, data.ChannelType, data.ChannelTarget) {
+			return true
+		}
+		var alert models.AlertDataSourceModel
+		resp.Diagnostics.Append(alert.FromApiModel(ctx, a)...)
+		data.Names = append(data.Names, a.Id)
+		data.Alerts = append(data.Alerts, alert)
+		return true
+	})
+	if err != nil {
+		addClientError(&resp.Diagnostics, "list alerts", err)
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// hasChannel reports whether alert has a channel matching channelType and channelTarget,
+// either of which can be null to match any value.
+func hasChannel(alert *client.Alert, channelType, channelTarget types.String) bool {
+	if channelType.IsNull() && channelTarget.IsNull() {
+		return true
+	}
+	for _, c := range alert.Channels {
+		if !channelType.IsNull() && c.Type != channelType.ValueString() {
+			continue
+		}
+		if channelTarget.IsNull() || containsString(c.Targets, channelTarget.ValueString()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/baselime/terraform-provider-baselime/client/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"math/big"
+	"testing"
+)
+
+func TestAccAlertsDataSource(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	if err := srv.Put(fakeapi.Queries, testAccSharedQuery()); err != nil {
+		t.Fatal(err)
+	}
+	for id, channels := range map[string][]client.AlertChannel{
+		"checkout-errors": {{Type: "slack", Targets: []string{"oncall"}}, {Type: "email", Targets: []string{"checkout@example.com"}}},
+		"search-errors":   {{Type: "slack", Targets: []string{"search"}}},
+		"search-latency":  {{Type: "email", Targets: []string{"oncall"}}},
+	} {
+		err := srv.Put(fakeapi.Alerts, &client.Alert{
+			Id:       id,
+			Enabled:  true,
+			Channels: channels,
+			Parameters: client.AlertParameters{
+				QueryId:   "shared-query",
+				Threshold: client.AlertThreshold{Operation: ">", Value: big.NewFloat(0)},
+				Frequency: "5m",
+				Window:    "5m",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+data "baselime_alerts" "slack" {
+  channel_type = "slack"
+}
+
+data "baselime_alerts" "oncall" {
+  channel_target = "oncall"
+}
+
+data "baselime_alerts" "oncall_slack" {
+  channel_type   = "slack"
+  channel_target = "oncall"
+}
+
+data "baselime_alerts" "search" {
+  name_regex = "^search-"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.baselime_alerts.slack", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.baselime_alerts.oncall", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.baselime_alerts.oncall_slack", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.baselime_alerts.oncall_slack", "names.0", "checkout-errors"),
+					resource.TestCheckResourceAttr("data.baselime_alerts.oncall_slack", "alerts.0.channels.#", "2"),
+					resource.TestCheckResourceAttr("data.baselime_alerts.search", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.baselime_alerts.search", "alerts.1.name", "search-latency"),
+				),
+			},
+		},
+	})
+}
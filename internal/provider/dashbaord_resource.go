@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"github.com/baselime/terraform-provider-baselime/client"
 	"github.com/baselime/terraform-provider-baselime/internal/models"
-	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -36,7 +38,43 @@ func (r *DashboardResource) Schema(ctx context.Context, req resource.SchemaReque
 		MarkdownDescription: "Dashboard resource",
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
-				Required: true,
+				Required:            true,
+				MarkdownDescription: "Dashboard name. Changing it replaces the dashboard, as the name is its ID in the Baselime API",
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceOnRename("dashboard"),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Dashboard ID, the same as its name",
+				PlanModifiers: []planmodifier.String{
+					idFromName(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Time the dashboard was created",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"updated_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Time the dashboard was last updated",
+			},
+			"created_by": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the user who created the dashboard",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"url": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Link to the dashboard in the Baselime console",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"description": schema.StringAttribute{
 				Optional: true,
@@ -45,14 +83,18 @@ func (r *DashboardResource) Schema(ctx context.Context, req resource.SchemaReque
 				Required:            true,
 				MarkdownDescription: "Dashboard widgets",
 				ElementType: types.ObjectType{
-					AttrTypes: map[string]attr.Type{
-						"query_id":    types.StringType,
-						"type":        types.StringType,
-						"name":        types.StringType,
-						"description": types.StringType,
-					},
+					AttrTypes: models.DashboardWidgetAttrTypes,
 				},
 			},
+			"parameters_json": parametersJSONAttribute("dashboard", client.DashboardParameters{}),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -83,11 +125,27 @@ func (r *DashboardResource) Create(ctx context.Context, req resource.CreateReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := r.client.CreateDashboard(ctx, data.ToApiModel())
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	dashboard, diags := data.ToApiModel(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	err := r.client.CreateDashboard(ctx, dashboard)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create dashboard, got error: %s", err))
+		addClientError(&resp.Diagnostics, "create dashboard", err)
 		return
 	}
+	data.FromApiMetadata(dashboard)
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, dashboard.ETag)...)
 	tflog.Trace(ctx, "created a resource")
 
 	// Save data into Terraform state
@@ -100,13 +158,34 @@ func (r *DashboardResource) Read(ctx context.Context, req resource.ReadRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	dashboard, err := r.client.GetDashboard(ctx, data.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read dashboard, got error: %s", err))
+		addClientError(&resp.Diagnostics, "read dashboard", err)
 		return
 	}
-	data.FromApiModel(dashboard)
+	if dashboard == nil {
+		tflog.Warn(ctx, "dashboard not found, removing it from state", map[string]interface{}{
+			"name": data.Name.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	checkDrift := hasETag(ctx, req.Private)
+	resp.Diagnostics.Append(data.FromApiModel(ctx, dashboard)...)
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, dashboard.ETag)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if checkDrift && !resp.Diagnostics.HasError() {
+		addDriftWarning(&resp.Diagnostics, "dashboard", dashboard.Id, req.State.Raw, resp.State.Raw)
+	}
 }
 
 func (r *DashboardResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -117,11 +196,32 @@ func (r *DashboardResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	err := r.client.UpdateDashboard(ctx, data.ToApiModel())
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	dashboard, diags := data.ToApiModel(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	dashboard.ETag, diags = getETag(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.UpdateDashboard(ctx, dashboard)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update dashboard, got error: %s", err))
+		addClientError(&resp.Diagnostics, "update dashboard", err)
 		return
 	}
+	data.FromApiMetadata(dashboard)
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, dashboard.ETag)...)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -136,9 +236,18 @@ func (r *DashboardResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	err := r.client.DeleteDashboard(ctx, data.Name.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete dashboard, got error: %s", err))
+	// an object already deleted outside Terraform is gone as asked
+	if err != nil && !client.IsNotFound(err) {
+		addClientError(&resp.Diagnostics, "delete dashboard", err)
 		return
 	}
 }
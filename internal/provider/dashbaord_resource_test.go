@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/client/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"strings"
+	"testing"
+)
+
+func TestAccDashboardResource(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(srv, fakeapi.Dashboards, "baselime_dashboard"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(srv) + testAccDashboardConfig("timeseries"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckExists(srv, fakeapi.Dashboards, "baselime_dashboard.test"),
+					resource.TestCheckResourceAttr("baselime_dashboard.test", "name", "acc-dashboard"),
+					resource.TestCheckResourceAttr("baselime_dashboard.test", "widgets.#", "1"),
+					resource.TestCheckResourceAttr("baselime_dashboard.test", "widgets.0.query_id", "acc-query"),
+					resource.TestCheckResourceAttr("baselime_dashboard.test", "widgets.0.type", "timeseries"),
+					resource.TestCheckResourceAttr("baselime_dashboard.test", "id", "acc-dashboard"),
+					resource.TestCheckResourceAttr("baselime_dashboard.test", "url", "https://console.baselime.io/test-workspace/test-environment/dashboards/acc-dashboard"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:                         "baselime_dashboard.test",
+				ImportState:                          true,
+				ImportStateId:                        "acc-dashboard",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+				ImportStateVerifyIgnore:              []string{"timeouts"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(srv) + testAccDashboardConfig("table"),
+				Check:  resource.TestCheckResourceAttr("baselime_dashboard.test", "widgets.0.type", "table"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply:             []plancheck.PlanCheck{plancheck.ExpectResourceAction("baselime_dashboard.test", plancheck.ResourceActionUpdate)},
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+		},
+	})
+}
+
+func TestAccDashboardResource_disappears(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccDashboardConfig("timeseries"),
+				Check:  testAccCheckExists(srv, fakeapi.Dashboards, "baselime_dashboard.test"),
+			},
+			// Deleting the dashboard outside Terraform plans a re-create
+			{
+				PreConfig: func() { srv.Delete(fakeapi.Dashboards, "acc-dashboard") },
+				Config:    testAccProviderConfig(srv) + testAccDashboardConfig("timeseries"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("baselime_dashboard.test", plancheck.ResourceActionCreate)},
+				},
+				Check: testAccCheckExists(srv, fakeapi.Dashboards, "baselime_dashboard.test"),
+			},
+			// Deleting the dashboard outside Terraform between refresh and destroy
+			{
+				Config:  testAccProviderConfig(srv) + testAccDashboardConfig("timeseries"),
+				Destroy: true,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("baselime_dashboard.test", plancheck.ResourceActionDestroy),
+						testAccEditBeforeApply(func() { srv.Delete(fakeapi.Dashboards, "acc-dashboard") }),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccDashboardResource_parametersJSON(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	config := testAccProviderConfig(srv) + strings.Replace(testAccDashboardConfig("timeseries"), `  widgets = [
+`, `  parameters_json = jsonencode({ layout = { columns = 2 } })
+  widgets = [
+`, 1)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(srv, fakeapi.Dashboards, "baselime_dashboard"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: func(*terraform.State) error {
+					d, _ := srv.Get(fakeapi.Dashboards, "acc-dashboard")
+					params := d["parameters"].(map[string]interface{})
+					layout, _ := params["layout"].(map[string]interface{})
+					if layout["columns"] != float64(2) || len(params["widgets"].([]interface{})) != 1 {
+						return fmt.Errorf("unexpected parameters in the API: %v", params)
+					}
+					return nil
+				},
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+		},
+	})
+}
+
+func TestAccDashboardResource_nullAttributes(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	// the API leaves out empty descriptions and names
+	config := testAccProviderConfig(srv) + strings.NewReplacer(
+		`  description = "Acceptance test dashboard"
+`, "",
+		`name        = "Errors"`, `name        = null`,
+		`description = "Errors over time"`, `description = null`,
+	).Replace(testAccDashboardConfig("timeseries"))
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(srv, fakeapi.Dashboards, "baselime_dashboard"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("baselime_dashboard.test", "description"),
+					resource.TestCheckNoResourceAttr("baselime_dashboard.test", "widgets.0.name"),
+					resource.TestCheckNoResourceAttr("baselime_dashboard.test", "widgets.0.description"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			// imported widgets have no state to tell empty names from null ones
+			{
+				ResourceName:                         "baselime_dashboard.test",
+				ImportState:                          true,
+				ImportStateId:                        "acc-dashboard",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+				ImportStateVerifyIgnore:              []string{"timeouts", "widgets.0.name", "widgets.0.description"},
+			},
+		},
+	})
+}
+
+func testAccDashboardConfig(widgetType string) string {
+	return testAccQueryConfig + fmt.Sprintf(`
+resource "baselime_dashboard" "test" {
+  name        = "acc-dashboard"
+  description = "Acceptance test dashboard"
+  widgets = [
+    {
+      query_id    = baselime_query.test.name
+      type        = %q
+      name        = "Errors"
+      description = "Errors over time"
+    }
+  ]
+}
+`, widgetType)
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/baselime/terraform-provider-baselime/internal/models"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &DashboardDataSource{}
+
+func NewDashboardDataSource() datasource.DataSource {
+	return &DashboardDataSource{}
+}
+
+// DashboardDataSource defines the data source implementation.
+type DashboardDataSource struct {
+	client *client.Client
+}
+
+func (d *DashboardDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dashboard"
+}
+
+func (d *DashboardDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := dashboardDataSourceAttributes()
+	attributes["name"] = schema.StringAttribute{
+		Required:            true,
+		MarkdownDescription: "Dashboard name",
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Looks up an existing dashboard by name.",
+		Attributes:          attributes,
+	}
+}
+
+// dashboardDataSourceAttributes returns the computed attributes of a dashboard, shared by the
+// baselime_dashboard and baselime_dashboards data sources.
+func dashboardDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Dashboard name",
+		},
+		"id": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Dashboard ID, the same as its name",
+		},
+		"created_at": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Time the dashboard was created",
+		},
+		"updated_at": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Time the dashboard was last updated",
+		},
+		"created_by": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "ID of the user who created the dashboard",
+		},
+		"url": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Link to the dashboard in the Baselime console",
+		},
+		"description": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Dashboard description",
+		},
+		"widgets": schema.ListAttribute{
+			Computed:            true,
+			MarkdownDescription: "Dashboard widgets",
+			ElementType: types.ObjectType{
+				AttrTypes: models.DashboardWidgetAttrTypes,
+			},
+		},
+	}
+}
+
+func (d *DashboardDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*DataSourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *DataSourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = provider.Client
+}
+
+func (d *DashboardDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data models.DashboardDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dashboard, err := d.client.GetDashboard(ctx, data.Name.ValueString())
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read dashboard", err)
+		return
+	}
+	if dashboard == nil {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Dashboard not found",
+			fmt.Sprintf("No dashboard named %q exists in the workspace and environment of the API key.", data.Name.ValueString()))
+		return
+	}
+	resp.Diagnostics.Append(data.FromApiModel(ctx, dashboard)...)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/baselime/terraform-provider-baselime/client/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"testing"
+)
+
+func TestAccDashboardDataSource(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	if err := srv.Put(fakeapi.Queries, testAccSharedQuery()); err != nil {
+		t.Fatal(err)
+	}
+	err := srv.Put(fakeapi.Dashboards, &client.Dashboard{
+		Id:          "shared-dashboard",
+		Description: "Shared dashboard",
+		Parameters: client.DashboardParameters{
+			Widgets: []client.DashboardWidget{{QueryId: "shared-query", Type: client.WidgetTypeStatistic, Name: "Errors"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A service team adds a widget for the shared query to its own dashboard
+			{
+				Config: testAccProviderConfig(srv) + `
+data "baselime_query" "shared" {
+  name = "shared-query"
+}
+
+data "baselime_dashboard" "test" {
+  name = "shared-dashboard"
+}
+
+resource "baselime_dashboard" "test" {
+  name        = "service-dashboard"
+  description = "Service dashboard"
+  widgets = concat(data.baselime_dashboard.test.widgets, [
+    {
+      query_id    = data.baselime_query.shared.name
+      type        = "timeseries"
+      name        = "Errors over time"
+      description = ""
+    }
+  ])
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.baselime_dashboard.test", "description", "Shared dashboard"),
+					resource.TestCheckResourceAttr("data.baselime_dashboard.test", "widgets.0.type", "statistic"),
+					resource.TestCheckResourceAttr("baselime_dashboard.test", "widgets.#", "2"),
+					resource.TestCheckResourceAttr("baselime_dashboard.test", "widgets.1.query_id", "shared-query"),
+				),
+			},
+		},
+	})
+}
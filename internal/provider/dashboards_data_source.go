@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/baselime/terraform-provider-baselime/internal/models"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &DashboardsDataSource{}
+
+func NewDashboardsDataSource() datasource.DataSource {
+	return &DashboardsDataSource{}
+}
+
+// DashboardsDataSource defines the data source implementation.
+type DashboardsDataSource struct {
+	client *client.Client
+}
+
+func (d *DashboardsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dashboards"
+}
+
+func (d *DashboardsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the dashboards matching all of the given filters.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only include dashboards whose name matches this regular expression",
+			},
+			"description_contains": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only include dashboards whose description contains this string",
+			},
+			"query_id": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only include dashboards with a widget for this query",
+			},
+			"names": schema.ListAttribute{
+				Computed:            true,
+				MarkdownDescription: "Names of the matching dashboards",
+				ElementType:         types.StringType,
+			},
+			"dashboards": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Matching dashboards",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: dashboardDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *DashboardsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*DataSourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *DataSourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = provider.Client
+}
+
+func (d *DashboardsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data models.DashboardsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filter := newListFilter(data.NameRegex, data.DescriptionContains, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Names = []string{}
+	data.Dashboards = []models.DashboardDataSourceModel{}
+	err := d.client.ListDashboards(ctx, nil, func(db *client.Dashboard) bool {
+		if !filter.matches(db.Id, db.Description) {
+			return true
+		}
+		if !data.QueryId.IsNull() && !hasWidgetFor(db, data.QueryId.ValueString()) {
+			return true
+		}
+		var dashboard models.DashboardDataSourceModel
+		resp.Diagnostics.Append(dashboard.FromApiModel(ctx, db)...)
+		data.Names = append(data.Names, db.Id)
+		data.Dashboards = append(data.Dashboards, dashboard)
+		return true
+	})
+	if err != nil {
+		addClientError(&resp.Diagnostics, "list dashboards", err)
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// hasWidgetFor reports whether dashboard has a widget showing queryId.
+func hasWidgetFor(dashboard *client.Dashboard, queryId string) bool {
+	for _, w := range dashboard.Parameters.Widgets {
+		if w.QueryId == queryId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/baselime/terraform-provider-baselime/client/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"testing"
+)
+
+func TestAccDashboardsDataSource(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	for _, id := range []string{"shared-query", "other-query"} {
+		query := testAccSharedQuery()
+		query.Id = id
+		if err := srv.Put(fakeapi.Queries, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for id, queryId := range map[string]string{"checkout": "shared-query", "search": "other-query"} {
+		err := srv.Put(fakeapi.Dashboards, &client.Dashboard{
+			Id:          id,
+			Description: "Dashboard of the " + id + " service",
+			Parameters: client.DashboardParameters{
+				Widgets: []client.DashboardWidget{{QueryId: queryId, Type: client.WidgetTypeTable}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+data "baselime_dashboards" "all" {
+  description_contains = "service"
+}
+
+data "baselime_dashboards" "shared" {
+  query_id = "shared-query"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.baselime_dashboards.all", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.baselime_dashboards.shared", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.baselime_dashboards.shared", "dashboards.0.name", "checkout"),
+					resource.TestCheckResourceAttr("data.baselime_dashboards.shared", "dashboards.0.widgets.0.query_id", "shared-query"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"strings"
+)
+
+// addClientError appends a diagnostic for an error returned by the Baselime client.
+// action describes what was being attempted, for example "create query".
+func addClientError(diags *diag.Diagnostics, action string, err error) {
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to %s, got error: %s", action, err))
+		return
+	}
+	if client.IsPreconditionFailed(err) {
+		diags.AddError(
+			fmt.Sprintf("Unable to %s: modified outside Terraform", action),
+			"The object was changed in Baselime after Terraform last read it, so applying this plan "+
+				"would overwrite those changes. Refresh the state and re-plan, for example by running "+
+				"terraform plan again, to review the changes before applying.\n\n"+apiErrorDetail(apiErr),
+		)
+		return
+	}
+	diags.AddError(fmt.Sprintf("Unable to %s", action), apiErrorDetail(apiErr))
+}
+
+// apiErrorDetail renders an APIError as a human-readable diagnostic detail.
+func apiErrorDetail(apiErr *client.APIError) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "The Baselime API responded with %s", apiErr.Status)
+	if apiErr.Message != "" {
+		fmt.Fprintf(&sb, ": %s", apiErr.Message)
+	}
+	sb.WriteString("\n")
+	if len(apiErr.Details) > 0 {
+		sb.WriteString("\n")
+		for _, d := range apiErr.Details {
+			if d.Field != "" {
+				fmt.Fprintf(&sb, "  - %s: %s\n", d.Field, d.Message)
+			} else {
+				fmt.Fprintf(&sb, "  - %s\n", d.Message)
+			}
+		}
+	}
+	fmt.Fprintf(&sb, "\nRequest: %s %s", apiErr.Method, apiErr.Path)
+	if apiErr.RequestID != "" {
+		fmt.Fprintf(&sb, "\nRequest ID: %s", apiErr.RequestID)
+	}
+	return sb.String()
+}
@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"sort"
+	"strings"
+)
+
+// addDriftWarning warns about the attributes of an object that changed outside Terraform,
+// by comparing the prior state with the state read from the API. kind is the type of
+// object, for example "query".
+func addDriftWarning(diags *diag.Diagnostics, kind, name string, prior, current tftypes.Value) {
+	fields, err := driftedFields(prior, current)
+	if err != nil || len(fields) == 0 {
+		return
+	}
+	diags.AddWarning(
+		fmt.Sprintf("%s%s changed outside Terraform", strings.ToUpper(kind[:1]), kind[1:]),
+		fmt.Sprintf("The %s %q no longer matches the last applied configuration. These attributes were changed:\n\n  - %s\n\n"+
+			"Review the plan to revert them, or update the configuration to keep them.",
+			kind, name, strings.Join(fields, "\n  - ")),
+	)
+}
+
+// driftIgnoredAttributes are not listed as drift: the timeouts are not part of the
+// object, and the metadata computed by the API changes with every change made outside
+// Terraform.
+var driftIgnoredAttributes = map[tftypes.AttributeName]bool{
+	"timeouts":   true,
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+	"created_by": true,
+	"url":        true,
+}
+
+// driftedFields returns the sorted paths of the attributes that differ between prior and
+// current, leaving out objects and collections whose nested values are listed themselves.
+func driftedFields(prior, current tftypes.Value) ([]string, error) {
+	diffs, err := prior.Diff(current)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []string
+	for _, d := range diffs {
+		steps := d.Path.Steps()
+		if len(steps) == 0 {
+			continue
+		}
+		if name, ok := steps[0].(tftypes.AttributeName); ok && driftIgnoredAttributes[name] {
+			continue
+		}
+		if isContainer(d.Value1) && isContainer(d.Value2) {
+			continue
+		}
+		candidates = append(candidates, formatPath(steps))
+	}
+
+	// A value that was added or removed as a whole is listed once, without its nested values.
+	var fields []string
+	for _, field := range candidates {
+		if !hasAncestor(field, candidates) && !containsString(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// hasAncestor reports whether one of fields is a parent path of field.
+func hasAncestor(field string, fields []string) bool {
+	for _, f := range fields {
+		if strings.HasPrefix(field, f+".") || strings.HasPrefix(field, f+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// isContainer reports whether v is a known, non-null object or collection.
+func isContainer(v *tftypes.Value) bool {
+	if v == nil || v.IsNull() || !v.IsKnown() {
+		return false
+	}
+	t := v.Type()
+	return t.Is(tftypes.Object{}) || t.Is(tftypes.List{}) || t.Is(tftypes.Set{}) || t.Is(tftypes.Map{}) || t.Is(tftypes.Tuple{})
+}
+
+// formatPath renders steps the way they are written in a configuration, for example filters[0].value.
+func formatPath(steps []tftypes.AttributePathStep) string {
+	var sb strings.Builder
+	for i, step := range steps {
+		switch s := step.(type) {
+		case tftypes.AttributeName:
+			if i > 0 {
+				sb.WriteString(".")
+			}
+			sb.WriteString(string(s))
+		case tftypes.ElementKeyInt:
+			fmt.Fprintf(&sb, "[%d]", int64(s))
+		case tftypes.ElementKeyString:
+			fmt.Fprintf(&sb, "[%q]", string(s))
+		case tftypes.ElementKeyValue:
+			sb.WriteString("[*]")
+		}
+	}
+	return sb.String()
+}
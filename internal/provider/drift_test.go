@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"reflect"
+	"testing"
+)
+
+func TestDriftedFields(t *testing.T) {
+	filterType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"key": tftypes.String, "value": tftypes.String}}
+	stateType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":        tftypes.String,
+		"description": tftypes.String,
+		"filters":     tftypes.List{ElementType: filterType},
+		"updated_at":  tftypes.String,
+		"timeouts":    tftypes.Object{AttributeTypes: map[string]tftypes.Type{"read": tftypes.String}},
+	}}
+	filter := func(key, value string) tftypes.Value {
+		return tftypes.NewValue(filterType, map[string]tftypes.Value{
+			"key":   tftypes.NewValue(tftypes.String, key),
+			"value": tftypes.NewValue(tftypes.String, value),
+		})
+	}
+	state := func(description string, filters interface{}, read interface{}, updatedAt string) tftypes.Value {
+		return tftypes.NewValue(stateType, map[string]tftypes.Value{
+			"name":        tftypes.NewValue(tftypes.String, "errors"),
+			"description": tftypes.NewValue(tftypes.String, description),
+			"filters":     tftypes.NewValue(tftypes.List{ElementType: filterType}, filters),
+			"updated_at":  tftypes.NewValue(tftypes.String, updatedAt),
+			"timeouts": tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{"read": tftypes.String}}, map[string]tftypes.Value{
+				"read": tftypes.NewValue(tftypes.String, read),
+			}),
+		})
+	}
+	prior := state("Errors", []tftypes.Value{filter("message", "error")}, nil, "2023-01-01")
+
+	tests := []struct {
+		name    string
+		current tftypes.Value
+		want    []string
+	}{
+		{
+			name:    "no drift",
+			current: state("Errors", []tftypes.Value{filter("message", "error")}, nil, "2023-01-01"),
+		},
+		{
+			name:    "changed attributes",
+			current: state("Edited", []tftypes.Value{filter("message", "warning")}, nil, "2023-01-01"),
+			want:    []string{"description", "filters[0].value"},
+		},
+		{
+			name:    "added list element",
+			current: state("Errors", []tftypes.Value{filter("message", "error"), filter("level", "error")}, nil, "2023-01-01"),
+			want:    []string{"filters[1]"},
+		},
+		{
+			name:    "removed list element",
+			current: state("Errors", []tftypes.Value{}, nil, "2023-01-01"),
+			want:    []string{"filters[0]"},
+		},
+		{
+			name:    "null list",
+			current: state("Errors", nil, nil, "2023-01-01"),
+			want:    []string{"filters"},
+		},
+		{
+			name:    "computed metadata is ignored",
+			current: state("Errors", []tftypes.Value{filter("message", "error")}, nil, "2023-02-01"),
+		},
+		{
+			name:    "metadata changed with other attributes",
+			current: state("Edited", []tftypes.Value{filter("message", "error")}, nil, "2023-02-01"),
+			want:    []string{"description"},
+		},
+		{
+			name:    "timeouts are ignored",
+			current: state("Errors", []tftypes.Value{filter("message", "error")}, "5m", "2023-01-01"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := driftedFields(prior, tt.current)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("driftedFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// etagPrivateKey is the private state key holding the ETag of the object last read from
+// or written to the API. Updates send it as If-Match so that changes made outside
+// Terraform since then are reported instead of overwritten.
+const etagPrivateKey = "etag"
+
+// privateState is implemented by the Private field of resource requests and responses.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// getETag returns the ETag stored in private state, or an empty string if there is none.
+func getETag(ctx context.Context, private privateState) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	b, getDiags := private.GetKey(ctx, etagPrivateKey)
+	diags.Append(getDiags...)
+	if diags.HasError() || len(b) == 0 {
+		return "", diags
+	}
+	var etag string
+	if err := json.Unmarshal(b, &etag); err != nil {
+		diags.AddError("Invalid Private State", "Unable to read the ETag stored in private state: "+err.Error())
+	}
+	return etag, diags
+}
+
+// hasETag reports whether private state holds an ETag, even an empty one. This is the case
+// once the object has been created, read or updated, but not right after an import.
+func hasETag(ctx context.Context, private privateState) bool {
+	b, diags := private.GetKey(ctx, etagPrivateKey)
+	return !diags.HasError() && len(b) > 0
+}
+
+// setETag stores etag in private state.
+func setETag(ctx context.Context, private privateState, etag string) diag.Diagnostics {
+	b, _ := json.Marshal(etag)
+	return private.SetKey(ctx, etagPrivateKey, b)
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"regexp"
+	"strings"
+)
+
+// listFilter selects objects by name and description in the plural data sources.
+type listFilter struct {
+	nameRegex           *regexp.Regexp
+	descriptionContains string
+}
+
+// newListFilter builds a listFilter from the name_regex and description_contains attributes.
+func newListFilter(nameRegex, descriptionContains types.String, diags *diag.Diagnostics) *listFilter {
+	f := &listFilter{descriptionContains: descriptionContains.ValueString()}
+	if nameRegex.IsNull() || nameRegex.IsUnknown() {
+		return f
+	}
+	re, err := regexp.Compile(nameRegex.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("name_regex"), "Invalid regular expression",
+			fmt.Sprintf("name_regex must be a valid regular expression: %s", err))
+		return f
+	}
+	f.nameRegex = re
+	return f
+}
+
+func (f *listFilter) matches(name, description string) bool {
+	if f.nameRegex != nil && !f.nameRegex.MatchString(name) {
+		return false
+	}
+	return strings.Contains(description, f.descriptionContains)
+}
+
+// containsString reports whether values contains v.
+func containsString(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
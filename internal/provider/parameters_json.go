@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/baselime/terraform-provider-baselime/internal/models"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"strings"
+)
+
+// parametersJSONAttribute returns the parameters_json attribute of a resource of kind,
+// such as query, whose parameters are decoded into params.
+func parametersJSONAttribute(kind string, params interface{}) schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:   true,
+		CustomType: jsontypes.NormalizedType{},
+		MarkdownDescription: fmt.Sprintf("JSON object merged into the parameters of the %s sent to the API, for the parameters "+
+			"the other attributes do not cover. Objects are merged with the parameters set by other attributes, "+
+			"which must not be set again. Formatting and key order are ignored when comparing it with the parameters "+
+			"of the %[1]s, such as with `jsonencode()`", kind),
+		Validators: []validator.String{parametersJSONValidator{params: params}},
+	}
+}
+
+// parametersJSONValidator checks that parameters_json is a JSON object that does not
+// set the parameters of a client parameters struct.
+type parametersJSONValidator struct {
+	params interface{}
+}
+
+func (v parametersJSONValidator) Description(ctx context.Context) string {
+	return "value must be a JSON object that does not set the parameters of other attributes"
+}
+
+func (v parametersJSONValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v parametersJSONValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	extra, err := models.DecodeParametersJSON(req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Parameters JSON",
+			fmt.Sprintf("parameters_json must be a JSON object, got error: %s", err))
+		return
+	}
+	if overlaps := client.OverlappingParameters(v.params, extra); len(overlaps) > 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Conflicting Parameters",
+			fmt.Sprintf("parameters_json sets parameters that other attributes set: %s. Use those attributes instead.",
+				strings.Join(overlaps, ", ")))
+	}
+}
@@ -2,17 +2,24 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"github.com/baselime/terraform-provider-baselime/client"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"time"
 )
 
 // Ensure BaselimeProvider satisfies various provider interfaces.
 var _ provider.Provider = &BaselimeProvider{}
 
+// defaultTimeout applies to resource operations without a matching timeouts entry.
+const defaultTimeout = 10 * time.Minute
+
 // BaselimeProvider defines the provider implementation.
 type BaselimeProvider struct {
 	// version is set to the provider version on release, "dev" when the
@@ -31,9 +38,27 @@ type DataSourceData struct {
 
 // BaselimeProviderModel describes the provider data model.
 type BaselimeProviderModel struct {
-	ApiHost   types.String `tfsdk:"api_host"`
-	ApiKey    types.String `tfsdk:"api_key" sensitive:"true"`
-	ApiScheme types.String `tfsdk:"api_scheme"`
+	ApiHost      types.String `tfsdk:"api_host"`
+	ApiKey       types.String `tfsdk:"api_key" sensitive:"true"`
+	ApiScheme    types.String `tfsdk:"api_scheme"`
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryMaxWait types.String `tfsdk:"retry_max_wait"`
+
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+	RequestTimeout        types.String  `tfsdk:"request_timeout"`
+
+	HTTPProxy          types.String `tfsdk:"http_proxy"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	ClientCertFile     types.String `tfsdk:"client_cert_file"`
+	ClientKeyFile      types.String `tfsdk:"client_key_file"`
+	ClientCertPEM      types.String `tfsdk:"client_cert_pem"`
+	ClientKeyPEM       types.String `tfsdk:"client_key_pem" sensitive:"true"`
+
+	Debug        types.Bool   `tfsdk:"debug"`
+	DebugHARFile types.String `tfsdk:"debug_har_file"`
 }
 
 func (p *BaselimeProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -53,6 +78,83 @@ func (p *BaselimeProvider) Schema(ctx context.Context, req provider.SchemaReques
 			"api_scheme": schema.StringAttribute{
 				Optional: true,
 			},
+			"max_retries": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: fmt.Sprintf("Maximum number of times a request is retried after a rate limit (429), "+
+					"a server error (5xx) or a network error. Defaults to `%d`. Set to `0` to disable retries.", client.DefaultMaxRetries),
+			},
+			"retry_max_wait": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: fmt.Sprintf("Maximum time to wait between two attempts, as a Go duration such as `30s`. "+
+					"Applies to both the exponential backoff and the `Retry-After` header. Defaults to `%s`.", client.DefaultRetryMaxWait),
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Optional: true,
+				MarkdownDescription: fmt.Sprintf("Maximum number of requests per second sent to the Baselime API, "+
+					"shared by all resources and data sources. Defaults to `%d`.", client.DefaultRequestsPerSecond),
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: fmt.Sprintf("Maximum number of requests in flight to the Baselime API at any time, "+
+					"shared by all resources and data sources. Defaults to `%d`.", client.DefaultMaxConcurrentRequests),
+			},
+			"request_timeout": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: fmt.Sprintf("Time limit of a single API call including its retries, as a Go duration "+
+					"such as `2m`. Defaults to `%s`.", client.DefaultRequestTimeout),
+			},
+			"http_proxy": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "URL of the proxy used to reach the Baselime API. Can also be set with the `BASELIME_HTTP_PROXY` " +
+					"environment variable. Defaults to the standard `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables.",
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Path to a PEM encoded CA bundle trusted in addition to the system roots, for example the CA of " +
+					"a TLS-intercepting proxy. Conflicts with `ca_cert_pem`. Can also be set with the `BASELIME_CA_CERT_FILE` environment variable.",
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "PEM encoded CA bundle trusted in addition to the system roots. Conflicts with `ca_cert_file`. " +
+					"Can also be set with the `BASELIME_CA_CERT_PEM` environment variable.",
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Disable the verification of the Baselime API TLS certificate. Only use this for debugging. " +
+					"Can also be set with the `BASELIME_INSECURE_SKIP_VERIFY` environment variable.",
+			},
+			"client_cert_file": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Path to a PEM encoded client certificate for mutual TLS. Requires a client key. " +
+					"Can also be set with the `BASELIME_CLIENT_CERT_FILE` environment variable.",
+			},
+			"client_key_file": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Path to the PEM encoded private key of the client certificate. " +
+					"Can also be set with the `BASELIME_CLIENT_KEY_FILE` environment variable.",
+			},
+			"client_cert_pem": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "PEM encoded client certificate for mutual TLS. Conflicts with `client_cert_file`. " +
+					"Can also be set with the `BASELIME_CLIENT_CERT_PEM` environment variable.",
+			},
+			"client_key_pem": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				MarkdownDescription: "PEM encoded private key of the client certificate. Conflicts with `client_key_file`. " +
+					"Can also be set with the `BASELIME_CLIENT_KEY_PEM` environment variable.",
+			},
+			"debug": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Log every request to and response from the Baselime API, with headers, bodies and timings, " +
+					"at the `DEBUG` level (`TF_LOG_PROVIDER=DEBUG`). API keys and alert channel targets are masked. " +
+					"Can also be set with the `BASELIME_DEBUG` environment variable.",
+			},
+			"debug_har_file": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "When `debug` is enabled, also record the API traffic to this HAR file, for example to share it " +
+					"with Baselime support. Can also be set with the `BASELIME_DEBUG_HAR_FILE` environment variable.",
+			},
 		},
 	}
 }
@@ -70,12 +172,69 @@ func (p *BaselimeProvider) Configure(ctx context.Context, req provider.Configure
 	// Configuration values are now available.
 	// if data.Endpoint.IsNull() { /* ... */ }
 
-	c := client.NewClient(&client.Config{
-		APIKey:    data.ApiKey.ValueString(),
-		APIHost:   data.ApiHost.ValueString(),
-		ApiScheme: data.ApiScheme.ValueString(),
-		Debug:     false,
+	maxRetries := 0
+	if !data.MaxRetries.IsNull() {
+		if data.MaxRetries.ValueInt64() < 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("max_retries"), "Invalid max_retries", "max_retries must not be negative.")
+		} else if data.MaxRetries.ValueInt64() == 0 {
+			maxRetries = -1
+		} else {
+			maxRetries = int(data.MaxRetries.ValueInt64())
+		}
+	}
+	retryMaxWait := parseDurationAttribute(data.RetryMaxWait, path.Root("retry_max_wait"), &resp.Diagnostics)
+	requestTimeout := parseDurationAttribute(data.RequestTimeout, path.Root("request_timeout"), &resp.Diagnostics)
+	if !data.RequestsPerSecond.IsNull() && data.RequestsPerSecond.ValueFloat64() <= 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("requests_per_second"), "Invalid requests_per_second", "requests_per_second must be greater than zero.")
+	}
+	if !data.MaxConcurrentRequests.IsNull() && data.MaxConcurrentRequests.ValueInt64() <= 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("max_concurrent_requests"), "Invalid max_concurrent_requests", "max_concurrent_requests must be greater than zero.")
+	}
+	conflicts := []struct {
+		file, pem types.String
+		fileAttr  string
+		pemAttr   string
+	}{
+		{data.CACertFile, data.CACertPEM, "ca_cert_file", "ca_cert_pem"},
+		{data.ClientCertFile, data.ClientCertPEM, "client_cert_file", "client_cert_pem"},
+		{data.ClientKeyFile, data.ClientKeyPEM, "client_key_file", "client_key_pem"},
+	}
+	for _, c := range conflicts {
+		if !c.file.IsNull() && !c.pem.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root(c.pemAttr), "Conflicting attributes",
+				fmt.Sprintf("Only one of %s and %s can be set.", c.fileAttr, c.pemAttr))
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, err := client.NewClient(&client.Config{
+		APIKey:       data.ApiKey.ValueString(),
+		APIHost:      data.ApiHost.ValueString(),
+		ApiScheme:    data.ApiScheme.ValueString(),
+		Debug:        data.Debug.ValueBoolPointer(),
+		MaxRetries:   maxRetries,
+		RetryMaxWait: retryMaxWait,
+
+		RequestsPerSecond:     data.RequestsPerSecond.ValueFloat64(),
+		MaxConcurrentRequests: int(data.MaxConcurrentRequests.ValueInt64()),
+		RequestTimeout:        requestTimeout,
+
+		HTTPProxy:          data.HTTPProxy.ValueString(),
+		CACertFile:         data.CACertFile.ValueString(),
+		CACertPEM:          data.CACertPEM.ValueString(),
+		InsecureSkipVerify: data.InsecureSkipVerify.ValueBoolPointer(),
+		ClientCertFile:     data.ClientCertFile.ValueString(),
+		ClientKeyFile:      data.ClientKeyFile.ValueString(),
+		ClientCertPEM:      data.ClientCertPEM.ValueString(),
+		ClientKeyPEM:       data.ClientKeyPEM.ValueString(),
+		DebugHARFile:       data.DebugHARFile.ValueString(),
 	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to create the Baselime client", err.Error())
+		return
+	}
 	resp.DataSourceData = &DataSourceData{
 		Client: c,
 	}
@@ -84,6 +243,20 @@ func (p *BaselimeProvider) Configure(ctx context.Context, req provider.Configure
 	}
 }
 
+// parseDurationAttribute parses an optional positive duration attribute, returning zero when it is null.
+func parseDurationAttribute(v types.String, p path.Path, diags *diag.Diagnostics) time.Duration {
+	if v.IsNull() {
+		return 0
+	}
+	d, err := time.ParseDuration(v.ValueString())
+	if err != nil || d <= 0 {
+		diags.AddAttributeError(p, "Invalid duration",
+			fmt.Sprintf("%s must be a positive duration such as \"30s\", got %q.", p, v.ValueString()))
+		return 0
+	}
+	return d
+}
+
 func (p *BaselimeProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewQueryResource,
@@ -93,7 +266,14 @@ func (p *BaselimeProvider) Resources(ctx context.Context) []func() resource.Reso
 }
 
 func (p *BaselimeProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewQueryDataSource,
+		NewAlertDataSource,
+		NewDashboardDataSource,
+		NewQueriesDataSource,
+		NewAlertsDataSource,
+		NewDashboardsDataSource,
+	}
 }
 
 func New(version string) func() provider.Provider {
@@ -1 +1,111 @@
 package provider
+
+import (
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/client/fakeapi"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"testing"
+)
+
+const testAccAPIKey = "acceptance-test-key"
+
+// testAccProtoV6ProviderFactories are used to instantiate a provider during
+// acceptance testing. The factory function will be invoked for every Terraform
+// CLI command executed to create a provider server to which the CLI can
+// reattach.
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"baselime": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+// testAccFakeAPI starts a local stand-in of the Baselime API for the duration of the test.
+func testAccFakeAPI(t *testing.T) *fakeapi.Server {
+	t.Helper()
+	srv := fakeapi.NewServer(testAccAPIKey)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// testAccProviderConfig configures the provider to talk to srv.
+func testAccProviderConfig(srv *fakeapi.Server) string {
+	return fmt.Sprintf(`
+provider "baselime" {
+  api_key    = %q
+  api_host   = %q
+  api_scheme = "http"
+}
+`, testAccAPIKey, srv.Host())
+}
+
+// testAccQueryConfig is a query the alert and dashboard tests depend on.
+const testAccQueryConfig = `
+resource "baselime_query" "test" {
+  name        = "acc-query"
+  description = "Acceptance test query"
+  datasets    = ["lambda-logs"]
+  filters = [
+    {
+      key       = "message"
+      operation = "INCLUDES"
+      value     = "error"
+      type      = "string"
+    }
+  ]
+  filter_combination = "AND"
+  calculations = [
+    {
+      key      = ""
+      operator = "COUNT"
+      alias    = "count"
+    }
+  ]
+  group_by = [
+    {
+      type  = "string"
+      value = "message"
+    }
+  ]
+  order_by = {
+    value = "count"
+    order = "DESC"
+  }
+  limit = 10
+  needle = {
+    value      = "error"
+    is_regex   = false
+    match_case = false
+  }
+}
+`
+
+// testAccCheckExists verifies that the object named by the resource's name attribute exists in srv.
+func testAccCheckExists(srv *fakeapi.Server, kind fakeapi.Kind, resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", resourceName)
+		}
+		name := rs.Primary.Attributes["name"]
+		if _, ok := srv.Get(kind, name); !ok {
+			return fmt.Errorf("%s %s does not exist in the Baselime API", kind, name)
+		}
+		return nil
+	}
+}
+
+// testAccCheckDestroyed verifies that no object of kind is left in srv.
+func testAccCheckDestroyed(srv *fakeapi.Server, kind fakeapi.Kind, resourceType string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+			if _, ok := srv.Get(kind, rs.Primary.Attributes["name"]); ok {
+				return fmt.Errorf("%s %s still exists", kind, rs.Primary.Attributes["name"])
+			}
+		}
+		return nil
+	}
+}
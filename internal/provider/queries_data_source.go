@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/baselime/terraform-provider-baselime/internal/models"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &QueriesDataSource{}
+
+func NewQueriesDataSource() datasource.DataSource {
+	return &QueriesDataSource{}
+}
+
+// QueriesDataSource defines the data source implementation.
+type QueriesDataSource struct {
+	client *client.Client
+}
+
+func (d *QueriesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_queries"
+}
+
+func (d *QueriesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the queries matching all of the given filters.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only include queries whose name matches this regular expression",
+			},
+			"description_contains": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only include queries whose description contains this string",
+			},
+			"dataset": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only include queries on this dataset",
+			},
+			"names": schema.ListAttribute{
+				Computed:            true,
+				MarkdownDescription: "Names of the matching queries",
+				ElementType:         types.StringType,
+			},
+			"queries": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Matching queries",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: queryDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *QueriesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*DataSourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *DataSourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = provider.Client
+}
+
+func (d *QueriesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data models.QueriesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filter := newListFilter(data.NameRegex, data.DescriptionContains, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Names = []string{}
+	data.Queries = []models.QueryDataSourceModel{}
+	err := d.client.ListQueries(ctx, nil, func(q *client.Query) bool {
+		if !filter.matches(q.Id, q.Description) {
+			return true
+		}
+		if !data.Dataset.IsNull() && !containsString(q.Parameters.Datasets, data.Dataset.ValueString()) {
+			return true
+		}
+		var query models.QueryDataSourceModel
+		resp.Diagnostics.Append(query.FromApiObject(ctx, q)...)
+		data.Names = append(data.Names, q.Id)
+		data.Queries = append(data.Queries, query)
+		return true
+	})
+	if err != nil {
+		addClientError(&resp.Diagnostics, "list queries", err)
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
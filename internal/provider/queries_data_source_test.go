@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/baselime/terraform-provider-baselime/client/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestAccQueriesDataSource(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	for _, q := range []struct{ id, description, dataset string }{
+		{"api-errors", "Errors in the API", "lambda-logs"},
+		{"api-latency", "Latency of the API", "otel"},
+		{"web-errors", "Errors in the website", "lambda-logs"},
+	} {
+		query := testAccSharedQuery()
+		query.Id = q.id
+		query.Description = q.description
+		query.Parameters.Datasets = []string{q.dataset}
+		if err := srv.Put(fakeapi.Queries, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+data "baselime_queries" "all" {}
+
+data "baselime_queries" "api" {
+  name_regex = "^api-"
+}
+
+data "baselime_queries" "errors" {
+  description_contains = "Errors"
+  dataset              = "lambda-logs"
+}
+
+data "baselime_queries" "none" {
+  dataset = "missing"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.baselime_queries.all", "names.#", "3"),
+					resource.TestCheckResourceAttr("data.baselime_queries.api", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.baselime_queries.api", "names.0", "api-errors"),
+					resource.TestCheckResourceAttr("data.baselime_queries.api", "names.1", "api-latency"),
+					resource.TestCheckResourceAttr("data.baselime_queries.api", "queries.1.datasets.0", "otel"),
+					resource.TestCheckResourceAttr("data.baselime_queries.errors", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.baselime_queries.errors", "queries.0.name", "api-errors"),
+					resource.TestCheckResourceAttr("data.baselime_queries.errors", "queries.1.name", "web-errors"),
+					resource.TestCheckResourceAttr("data.baselime_queries.none", "names.#", "0"),
+					resource.TestCheckResourceAttr("data.baselime_queries.none", "queries.#", "0"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "baselime_queries" "invalid" {
+  name_regex = "api-("
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid regular expression`),
+			},
+		},
+	})
+}
+
+func TestAccQueriesDataSource_filterGroupsNestedTooDeeply(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	if err := srv.Put(fakeapi.Queries, testAccSharedQuery()); err != nil {
+		t.Fatal(err)
+	}
+	// a query saved in the console with 4 levels of filter groups
+	group := func(groups ...client.QueryFilterGroup) client.QueryFilterGroup {
+		level := client.StringFilterValue("error")
+		return client.QueryFilterGroup{
+			FilterCombination: "AND",
+			Filters:           []client.QueryFilter{{Key: "level", Operation: "=", Value: &level, Type: "string"}},
+			FilterGroups:      groups,
+		}
+	}
+	nested := testAccSharedQuery()
+	nested.Id = "nested-query"
+	nested.Parameters.FilterGroups = []client.QueryFilterGroup{group(group(group(group())))}
+	if err := srv.Put(fakeapi.Queries, nested); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+data "baselime_queries" "all" {}
+
+data "baselime_queries" "shared" {
+  name_regex = "^shared-"
+}
+
+data "baselime_query" "nested" {
+  name = "nested-query"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.baselime_queries.all", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.baselime_queries.all", "queries.0.name", "nested-query"),
+					resource.TestCheckResourceAttr("data.baselime_queries.all", "queries.0.filter_group.0.filter_group.0.filter_group.0.filters.0.key", "level"),
+					resource.TestCheckNoResourceAttr("data.baselime_queries.all", "queries.0.filter_group.0.filter_group.0.filter_group.0.filter_group"),
+					resource.TestCheckResourceAttr("data.baselime_queries.shared", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.baselime_query.nested", "filter_group.0.filter_group.0.filter_group.#", "1"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/baselime/terraform-provider-baselime/internal/models"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &QueryDataSource{}
+
+func NewQueryDataSource() datasource.DataSource {
+	return &QueryDataSource{}
+}
+
+// QueryDataSource defines the data source implementation.
+type QueryDataSource struct {
+	client *client.Client
+}
+
+func (d *QueryDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_query"
+}
+
+func (d *QueryDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := queryDataSourceAttributes()
+	attributes["name"] = schema.StringAttribute{
+		Required:            true,
+		MarkdownDescription: "Query name",
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Looks up an existing query by name.",
+		Attributes:          attributes,
+	}
+}
+
+// queryDataSourceAttributes returns the computed attributes of a query, shared by the
+// baselime_query and baselime_queries data sources.
+func queryDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Query name",
+		},
+		"id": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Query ID, the same as its name",
+		},
+		"created_at": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Time the query was created",
+		},
+		"updated_at": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Time the query was last updated",
+		},
+		"created_by": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "ID of the user who created the query",
+		},
+		"url": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Link to the query in the Baselime console",
+		},
+		"description": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Query description",
+		},
+		"datasets": schema.ListAttribute{
+			Computed:            true,
+			MarkdownDescription: "Query datasets",
+			ElementType:         types.StringType,
+		},
+		"filters": schema.ListAttribute{
+			Computed:            true,
+			MarkdownDescription: "Query filters",
+			ElementType:         types.ObjectType{AttrTypes: models.QueryFilterAttrTypes},
+		},
+		"filter_combination": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Query filter combination",
+		},
+		"filter_group": schema.ListAttribute{
+			Computed:            true,
+			MarkdownDescription: "Query filter groups, with their filters, filter combination and nested filter groups",
+			ElementType:         types.ObjectType{AttrTypes: models.FilterGroupAttrTypes(1)},
+		},
+		"calculations": schema.ListAttribute{
+			Computed:            true,
+			MarkdownDescription: "Query calculations",
+			ElementType:         types.ObjectType{AttrTypes: models.QueryCalculationAttrTypes},
+		},
+		"group_by": schema.ListAttribute{
+			Computed:            true,
+			MarkdownDescription: "Query group by",
+			ElementType:         types.ObjectType{AttrTypes: models.QueryGroupByAttrTypes},
+		},
+		"order_by": schema.ObjectAttribute{
+			Computed:       true,
+			AttributeTypes: models.QueryOrderByAttrTypes,
+		},
+		"limit": schema.Int64Attribute{
+			Computed:            true,
+			MarkdownDescription: "Query limit",
+		},
+		"needle": schema.ObjectAttribute{
+			Computed:       true,
+			AttributeTypes: models.SearchNeedleAttrTypes,
+		},
+		"time_range": schema.ObjectAttribute{
+			Computed:            true,
+			MarkdownDescription: "Query default time range",
+			AttributeTypes:      models.QueryTimeRangeAttrTypes,
+		},
+		"granularity": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Query granularity",
+		},
+		"having": schema.ListAttribute{
+			Computed:            true,
+			MarkdownDescription: "Query having clauses",
+			ElementType:         types.ObjectType{AttrTypes: models.QueryHavingAttrTypes},
+		},
+	}
+}
+
+func (d *QueryDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*DataSourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *DataSourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = provider.Client
+}
+
+func (d *QueryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data models.QueryDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	query, err := d.client.GetQuery(ctx, data.Name.ValueString())
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read query", err)
+		return
+	}
+	if query == nil {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Query not found",
+			fmt.Sprintf("No query named %q exists in the workspace and environment of the API key.", data.Name.ValueString()))
+		return
+	}
+	resp.Diagnostics.Append(data.FromApiObject(ctx, query)...)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
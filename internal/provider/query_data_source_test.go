@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/baselime/terraform-provider-baselime/client/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"regexp"
+	"testing"
+)
+
+// testAccSharedQuery is a query managed outside the configuration under test, for
+// example by a platform team in another Terraform workspace.
+func testAccSharedQuery() *client.Query {
+	message := client.StringFilterValue("error")
+	internalError, _ := client.NumberFilterValue("500")
+	badGateway, _ := client.NumberFilterValue("502")
+	status := client.ListFilterValue(internalError, badGateway)
+	return &client.Query{
+		Id:          "shared-query",
+		Description: "Shared query",
+		Parameters: client.QueryParameters{
+			Datasets: []string{"lambda-logs"},
+			Filters: []client.QueryFilter{
+				{Key: "message", Operation: "INCLUDES", Value: &message, Type: "string"},
+				{Key: "status", Operation: "IN", Value: &status, Type: "number"},
+			},
+			FilterCombination: "AND",
+			Calculations:      []client.QueryCalculation{{Operator: "COUNT", Alias: "count"}},
+			OrderBy:           &client.QueryOrderBy{Value: "count", Order: "DESC"},
+			Limit:             20,
+			TimeRange:         &client.QueryTimeRange{From: "1h"},
+			Having:            []client.QueryHaving{{Key: "count", Operation: ">=", Value: 10}},
+		},
+	}
+}
+
+func TestAccQueryDataSource(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	if err := srv.Put(fakeapi.Queries, testAccSharedQuery()); err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+data "baselime_query" "test" {
+  name = "shared-query"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.baselime_query.test", "description", "Shared query"),
+					resource.TestCheckResourceAttr("data.baselime_query.test", "datasets.0", "lambda-logs"),
+					resource.TestCheckResourceAttr("data.baselime_query.test", "filters.0.key", "message"),
+					resource.TestCheckResourceAttr("data.baselime_query.test", "filters.0.value", "error"),
+					resource.TestCheckNoResourceAttr("data.baselime_query.test", "filters.0.values"),
+					resource.TestCheckResourceAttr("data.baselime_query.test", "filters.1.value", ""),
+					resource.TestCheckResourceAttr("data.baselime_query.test", "filters.1.values.#", "2"),
+					resource.TestCheckResourceAttr("data.baselime_query.test", "filters.1.values.1", "502"),
+					resource.TestCheckResourceAttr("data.baselime_query.test", "filter_combination", "AND"),
+					resource.TestCheckResourceAttr("data.baselime_query.test", "calculations.0.operator", "COUNT"),
+					resource.TestCheckResourceAttr("data.baselime_query.test", "order_by.order", "DESC"),
+					resource.TestCheckResourceAttr("data.baselime_query.test", "limit", "20"),
+					resource.TestCheckNoResourceAttr("data.baselime_query.test", "needle"),
+					resource.TestCheckResourceAttr("data.baselime_query.test", "time_range.from", "1h"),
+					resource.TestCheckNoResourceAttr("data.baselime_query.test", "time_range.to"),
+					resource.TestCheckNoResourceAttr("data.baselime_query.test", "granularity"),
+					resource.TestCheckResourceAttr("data.baselime_query.test", "having.0.operation", ">="),
+					resource.TestCheckResourceAttr("data.baselime_query.test", "id", "shared-query"),
+					resource.TestCheckResourceAttr("data.baselime_query.test", "url", "https://console.baselime.io/test-workspace/test-environment/queries/shared-query"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(srv) + `
+data "baselime_query" "missing" {
+  name = "missing-query"
+}
+`,
+				ExpectError: regexp.MustCompile(`Query not found`),
+			},
+		},
+	})
+}
@@ -4,15 +4,23 @@ import (
 	"context"
 	"fmt"
 	"github.com/baselime/terraform-provider-baselime/client"
+	"github.com/baselime/terraform-provider-baselime/internal/filterexpr"
 	"github.com/baselime/terraform-provider-baselime/internal/models"
-	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strings"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -20,6 +28,10 @@ var _ resource.Resource = &QueryResource{}
 
 var _ resource.ResourceWithImportState = &QueryResource{}
 
+var _ resource.ResourceWithUpgradeState = &QueryResource{}
+
+var _ resource.ResourceWithModifyPlan = &QueryResource{}
+
 func NewQueryResource() resource.Resource {
 	return &QueryResource{}
 }
@@ -36,10 +48,46 @@ func (r *QueryResource) Metadata(ctx context.Context, req resource.MetadataReque
 func (r *QueryResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Query resource",
+		Version:             1,
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
 				Required:            true,
-				MarkdownDescription: "Query name",
+				MarkdownDescription: "Query name. Changing it replaces the query, as the name is its ID in the Baselime API",
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceOnRename("query"),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Query ID, the same as its name",
+				PlanModifiers: []planmodifier.String{
+					idFromName(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Time the query was created",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"updated_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Time the query was last updated",
+			},
+			"created_by": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the user who created the query",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"url": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Link to the query in the Baselime console",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"description": schema.StringAttribute{
 				Required:            true,
@@ -50,70 +98,285 @@ func (r *QueryResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				MarkdownDescription: "Query datasets",
 				ElementType:         types.StringType,
 			},
-			"filters": schema.ListAttribute{
-				Required:            true,
-				MarkdownDescription: "Query filters",
-				ElementType: types.ObjectType{
-					AttrTypes: map[string]attr.Type{
-						"key":       types.StringType,
-						"operation": types.StringType,
-						"value":     types.StringType,
-						"type":      types.StringType,
-					},
+			"filters": schema.ListNestedAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Query filters. Cannot be set together with `where`, and one of them or a `filter_group` is needed; with `where`, they are the filters of the expression",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: queryFilterAttributes(),
 				},
 			},
+			"where": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Query filters as an expression, for example `message INCLUDES 'error' AND @duration > 500`. " +
+					"Conditions are joined by `AND` or by `OR`, which sets `filter_combination`. " +
+					"Values are strings in single quotes, numbers or booleans, which sets the type of the filters. " +
+					"`IN` and `NOT_IN` take a list of values in parentheses, and `EXISTS` and `DOES_NOT_EXIST` take none. " +
+					"Keys containing spaces or other special characters are written in double quotes",
+			},
 			"filter_combination": schema.StringAttribute{
 				Optional:            true,
 				Default:             stringdefault.StaticString("OR"),
-				MarkdownDescription: "Query filter combination",
+				MarkdownDescription: "Query filter combination, joining the filters and the filter groups of the query. Cannot be set together with a `where` expression of several conditions",
 				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(models.FilterCombinationAnd), string(models.FilterCombinationOr)),
+				},
 			},
-			"calculations": schema.ListAttribute{
+			"calculations": schema.ListNestedAttribute{
 				Optional:            true,
 				MarkdownDescription: "Query calculations",
-				ElementType: types.ObjectType{
-					AttrTypes: map[string]attr.Type{
-						"key":      types.StringType,
-						"operator": types.StringType,
-						"alias":    types.StringType,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							Optional:            true,
+							Computed:            true,
+							Default:             stringdefault.StaticString(""),
+							MarkdownDescription: "Key of the event field to calculate on, not needed by `COUNT` and `RATE`",
+						},
+						"operator": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Calculation operator: " + calculationOperatorsDescription(),
+							Validators: []validator.String{
+								stringvalidator.OneOf(models.QueryCalculationOperators...),
+							},
+						},
+						"parameter": schema.Float64Attribute{
+							Optional:            true,
+							MarkdownDescription: "Parameter of the operator, the percentile of `PERCENTILE` and the interval in seconds of `RATE`",
+						},
+						"alias": schema.StringAttribute{
+							Optional:            true,
+							Computed:            true,
+							Default:             stringdefault.StaticString(""),
+							MarkdownDescription: "Name of the calculation in the results",
+						},
 					},
 				},
 			},
-			"group_by": schema.ListAttribute{
+			"group_by": schema.ListNestedAttribute{
 				Optional:            true,
 				MarkdownDescription: "Query group by",
-				ElementType: types.ObjectType{
-					AttrTypes: map[string]attr.Type{
-						"type":  types.StringType,
-						"value": types.StringType,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Optional:            true,
+							Computed:            true,
+							Default:             stringdefault.StaticString("string"),
+							MarkdownDescription: "Type of the field, one of " + markdownList(models.QueryValueTypes) + ". Defaults to `string`",
+							Validators: []validator.String{
+								stringvalidator.OneOf(models.QueryValueTypes...),
+							},
+						},
+						"value": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Key of the event field to group by",
+						},
 					},
 				},
 			},
-			"order_by": schema.ObjectAttribute{
-				Optional: true,
-				AttributeTypes: map[string]attr.Type{
-					"value": types.StringType,
-					"order": types.StringType,
+			"order_by": schema.SingleNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "Query order by",
+				Attributes: map[string]schema.Attribute{
+					"value": schema.StringAttribute{
+						Required:            true,
+						MarkdownDescription: "Alias of the calculation to order by",
+					},
+					"order": schema.StringAttribute{
+						Optional:            true,
+						Computed:            true,
+						Default:             stringdefault.StaticString("DESC"),
+						MarkdownDescription: "Sort order, one of " + markdownList(models.QueryOrders) + ". Defaults to `DESC`",
+						Validators: []validator.String{
+							stringvalidator.OneOf(models.QueryOrders...),
+						},
+					},
 				},
 			},
 			"limit": schema.Int64Attribute{
 				Optional:            true,
-				MarkdownDescription: "Query limit",
+				MarkdownDescription: fmt.Sprintf("Query limit, between %d and %d. Defaults to `50`", models.QueryMinLimit, models.QueryMaxLimit),
 				Default:             int64default.StaticInt64(50),
 				Computed:            true,
 			},
-			"needle": schema.ObjectAttribute{
-				Optional: true,
-				AttributeTypes: map[string]attr.Type{
-					"value":      types.StringType,
-					"is_regex":   types.BoolType,
-					"match_case": types.BoolType,
+			"needle": schema.SingleNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "Query search needle",
+				Attributes: map[string]schema.Attribute{
+					"value": schema.StringAttribute{
+						Required:            true,
+						MarkdownDescription: "Text searched for in the events",
+					},
+					"is_regex": schema.BoolAttribute{
+						Optional:            true,
+						Computed:            true,
+						Default:             booldefault.StaticBool(false),
+						MarkdownDescription: "Whether the value is a regular expression",
+					},
+					"match_case": schema.BoolAttribute{
+						Optional:            true,
+						Computed:            true,
+						Default:             booldefault.StaticBool(false),
+						MarkdownDescription: "Whether the search is case sensitive",
+					},
+				},
+			},
+			"time_range": schema.SingleNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "Time range the query runs on by default, relative to when it runs",
+				Attributes: map[string]schema.Attribute{
+					"from": schema.StringAttribute{
+						Required:            true,
+						MarkdownDescription: "How long before now the time range starts, a duration such as `1h` or `7d`",
+						Validators:          []validator.String{queryDurationValidator()},
+					},
+					"to": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "How long before now the time range ends, a duration shorter than `from`. Defaults to now",
+						Validators:          []validator.String{queryDurationValidator()},
+					},
+				},
+			},
+			"granularity": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Length of the time buckets of the results, a duration such as `1m`, shorter than the time range",
+				Validators:          []validator.String{queryDurationValidator()},
+			},
+			"having": schema.ListNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "Filters applied to the results of the calculations, such as `count > 100`. All of them must match",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Alias or operator of the calculation",
+						},
+						"operation": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Comparison, one of " + markdownList(models.QueryHavingOperations),
+							Validators: []validator.String{
+								stringvalidator.OneOf(models.QueryHavingOperations...),
+							},
+						},
+						"value": schema.Float64Attribute{
+							Required:            true,
+							MarkdownDescription: "Value the result of the calculation is compared with",
+						},
+					},
 				},
 			},
+			"parameters_json": parametersJSONAttribute("query", client.QueryParameters{}),
+		},
+		Blocks: map[string]schema.Block{
+			"filter_group": filterGroupBlock(1),
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
 
+// queryFilterAttributes returns the attributes of a filter, in the filters of a query and
+// of its filter groups.
+func queryFilterAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"key": schema.StringAttribute{
+			Required:            true,
+			MarkdownDescription: "Key of the event field to filter on",
+		},
+		"operation": schema.StringAttribute{
+			Required:            true,
+			MarkdownDescription: "Filter operation, one of " + markdownList(models.QueryFilterOperations),
+			Validators: []validator.String{
+				stringvalidator.OneOf(models.QueryFilterOperations...),
+			},
+		},
+		"value": schema.StringAttribute{
+			Optional:            true,
+			Computed:            true,
+			Default:             stringdefault.StaticString(""),
+			MarkdownDescription: "Value compared with the field, a number or `true` or `false` when the filter is of that `type`. Not needed by `EXISTS` and `DOES_NOT_EXIST`, and replaced by `values` for `IN` and `NOT_IN`",
+		},
+		"values": schema.ListAttribute{
+			Optional:            true,
+			ElementType:         types.StringType,
+			MarkdownDescription: "Values of `IN` and `NOT_IN` filters, of the `type` of the filter",
+		},
+		"type": schema.StringAttribute{
+			Optional:            true,
+			Computed:            true,
+			Default:             stringdefault.StaticString("string"),
+			MarkdownDescription: "Type of the value, one of " + markdownList(models.QueryValueTypes) + ". Defaults to `string`",
+			Validators: []validator.String{
+				stringvalidator.OneOf(models.QueryValueTypes...),
+			},
+		},
+	}
+}
+
+// filterGroupBlock returns the filter_group block at depth, starting from 1 for the groups
+// of a query. Groups nest down to models.MaxFilterGroupDepth.
+func filterGroupBlock(depth int) schema.ListNestedBlock {
+	block := schema.ListNestedBlock{
+		MarkdownDescription: "Group of filters, combined with the other filters and groups by `filter_combination` of the enclosing query or group. " +
+			fmt.Sprintf("Groups can be nested %d levels deep", models.MaxFilterGroupDepth),
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"filters": schema.ListNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Filters of the group",
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: queryFilterAttributes(),
+					},
+				},
+				"filter_combination": schema.StringAttribute{
+					Optional:            true,
+					Computed:            true,
+					Default:             stringdefault.StaticString("OR"),
+					MarkdownDescription: "How the filters and the nested groups of the group are combined, `AND` or `OR`. Defaults to `OR`",
+					Validators: []validator.String{
+						stringvalidator.OneOf(string(models.FilterCombinationAnd), string(models.FilterCombinationOr)),
+					},
+				},
+			},
+		},
+	}
+	if depth < models.MaxFilterGroupDepth {
+		block.NestedObject.Blocks = map[string]schema.Block{
+			"filter_group": filterGroupBlock(depth + 1),
+		}
+	}
+	return block
+}
+
+// calculationOperatorsDescription documents what each calculation operator calculates.
+func calculationOperatorsDescription() string {
+	descriptions := make([]string, len(models.QueryCalculationOperatorSpecs))
+	for i, op := range models.QueryCalculationOperatorSpecs {
+		descriptions[i] = "`" + op.Name + "` is " + op.Description
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+// queryDurationValidator checks the durations of the time range and the granularity of
+// queries.
+func queryDurationValidator() validator.String {
+	return stringvalidator.RegexMatches(models.QueryDurationPattern, "must be a number followed by s, m, h or d, such as 15m")
+}
+
+// markdownList formats values as a comma separated list of code spans.
+func markdownList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "`" + v + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
 func (r *QueryResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -141,11 +404,27 @@ func (r *QueryResource) Create(ctx context.Context, req resource.CreateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := r.client.CreateQuery(ctx, data.ToApiObject())
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	query, diags := data.ToApiObject(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	err := r.client.CreateQuery(ctx, query)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create query, got error: %s", err))
+		addClientError(&resp.Diagnostics, "create query", err)
 		return
 	}
+	data.FromApiMetadata(query)
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, query.ETag)...)
 	tflog.Trace(ctx, "query created", map[string]interface{}{
 		"name": data.Name,
 	})
@@ -163,15 +442,34 @@ func (r *QueryResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	query, err := r.client.GetQuery(ctx, data.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read query, got error: %s", err))
+		addClientError(&resp.Diagnostics, "read query", err)
 		return
 	}
-	if query != nil {
-		data.FromApiObject(query)
+	if query == nil {
+		tflog.Warn(ctx, "query not found, removing it from state", map[string]interface{}{
+			"name": data.Name.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
 	}
+	checkDrift := hasETag(ctx, req.Private)
+	resp.Diagnostics.Append(data.FromApiObject(ctx, query)...)
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, query.ETag)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if checkDrift && !resp.Diagnostics.HasError() {
+		addDriftWarning(&resp.Diagnostics, "query", query.Id, req.State.Raw, resp.State.Raw)
+	}
 }
 
 func (r *QueryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -182,11 +480,32 @@ func (r *QueryResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	err := r.client.UpdateQuery(ctx, data.ToApiObject())
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	query, diags := data.ToApiObject(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	query.ETag, diags = getETag(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.UpdateQuery(ctx, query)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update query, got error: %s", err))
+		addClientError(&resp.Diagnostics, "update query", err)
 		return
 	}
+	data.FromApiMetadata(query)
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, query.ETag)...)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -201,11 +520,87 @@ func (r *QueryResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	err := r.client.DeleteQuery(ctx, data.Name.ValueString())
+	// an object already deleted outside Terraform is gone as asked
+	if err != nil && !client.IsNotFound(err) {
+		addClientError(&resp.Diagnostics, "delete query", err)
+		return
+	}
+}
+
+// ModifyPlan plans the filters and the filter combination of a query written with a
+// filter expression, and the absent filters of a query made of filter groups only.
+func (r *QueryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// the resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var where types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("where"), &where)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if where.IsNull() {
+		// a query made of filter groups only has no filters
+		var filters types.List
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("filters"), &filters)...)
+		if filters.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("filters"), types.ListNull(types.ObjectType{AttrTypes: models.QueryFilterAttrTypes}))...)
+		}
+		return
+	}
+	if where.IsUnknown() {
+		var filterCombination types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("filter_combination"), &filterCombination)...)
+		if filterCombination.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("filter_combination"), types.StringUnknown())...)
+		}
+		return
+	}
+	filters, combination, err := filterexpr.Parse(where.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete query, got error: %s", err))
+		addFilterExpressionError(&resp.Diagnostics, where.ValueString(), err)
 		return
 	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("filters"), models.QueryFiltersFromApiModel(filters))...)
+	if combination != "" {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("filter_combination"), combination)...)
+	}
+	if resp.Diagnostics.HasError() || req.State.Raw.IsNull() {
+		return
+	}
+
+	// The default filter combination made the query look changed before the expression
+	// was taken into account, which left updated_at unknown. It keeps its value when the
+	// query turns out to be unchanged.
+	if equalExcept(req.State.Raw, resp.Plan.Raw, "updated_at") {
+		var updatedAt types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("updated_at"), &updatedAt)...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("updated_at"), updatedAt)...)
+	}
+}
+
+// equalExcept reports whether the objects a and b have the same attributes, apart from
+// the ones named in except.
+func equalExcept(a, b tftypes.Value, except ...string) bool {
+	var aAttrs, bAttrs map[string]tftypes.Value
+	if a.As(&aAttrs) != nil || b.As(&bAttrs) != nil || len(aAttrs) != len(bAttrs) {
+		return false
+	}
+	for name, v := range aAttrs {
+		if !containsString(except, name) && !v.Equal(bAttrs[name]) {
+			return false
+		}
+	}
+	return true
 }
 
 func (r *QueryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
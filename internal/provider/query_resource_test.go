@@ -0,0 +1,982 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/client/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestAccQueryResource(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(srv, fakeapi.Queries, "baselime_query"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProviderConfig(srv) + testAccQueryConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckExists(srv, fakeapi.Queries, "baselime_query.test"),
+					resource.TestCheckResourceAttr("baselime_query.test", "name", "acc-query"),
+					resource.TestCheckResourceAttr("baselime_query.test", "description", "Acceptance test query"),
+					resource.TestCheckResourceAttr("baselime_query.test", "datasets.#", "1"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.0.operation", "INCLUDES"),
+					resource.TestCheckResourceAttr("baselime_query.test", "calculations.0.alias", "count"),
+					resource.TestCheckResourceAttr("baselime_query.test", "limit", "10"),
+					resource.TestCheckResourceAttr("baselime_query.test", "id", "acc-query"),
+					resource.TestCheckResourceAttr("baselime_query.test", "created_by", "test-user"),
+					resource.TestCheckResourceAttrSet("baselime_query.test", "created_at"),
+					resource.TestCheckResourceAttrSet("baselime_query.test", "updated_at"),
+					resource.TestCheckResourceAttr("baselime_query.test", "url", "https://console.baselime.io/test-workspace/test-environment/queries/acc-query"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:                         "baselime_query.test",
+				ImportState:                          true,
+				ImportStateId:                        "acc-query",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+				ImportStateVerifyIgnore:              []string{"timeouts"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccProviderConfig(srv) + strings.Replace(testAccQueryConfig, `"Acceptance test query"`, `"Updated acceptance test query"`, 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("baselime_query.test", "description", "Updated acceptance test query"),
+					func(*terraform.State) error {
+						q, _ := srv.Get(fakeapi.Queries, "acc-query")
+						if q["description"] != "Updated acceptance test query" {
+							return fmt.Errorf("the query was not updated in the API: %v", q["description"])
+						}
+						return nil
+					},
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply:             []plancheck.PlanCheck{plancheck.ExpectResourceAction("baselime_query.test", plancheck.ResourceActionUpdate)},
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			// Changes made in the console are detected and reverted
+			{
+				PreConfig: func() {
+					q, _ := srv.Get(fakeapi.Queries, "acc-query")
+					q["description"] = "Edited in the console"
+					if err := srv.Put(fakeapi.Queries, q); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccProviderConfig(srv) + strings.Replace(testAccQueryConfig, `"Acceptance test query"`, `"Updated acceptance test query"`, 1),
+				Check:  resource.TestCheckResourceAttr("baselime_query.test", "description", "Updated acceptance test query"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("baselime_query.test", plancheck.ResourceActionUpdate)},
+				},
+			},
+		},
+	})
+}
+
+func TestAccQueryResource_modifiedBetweenPlanAndApply(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(srv, fakeapi.Queries, "baselime_query"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccQueryConfig,
+			},
+			// A change made in the console after the plan is not overwritten by the apply
+			{
+				Config: testAccProviderConfig(srv) + strings.Replace(testAccQueryConfig, `"Acceptance test query"`, `"Updated acceptance test query"`, 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("baselime_query.test", plancheck.ResourceActionUpdate),
+						testAccEditBeforeApply(func() {
+							q, _ := srv.Get(fakeapi.Queries, "acc-query")
+							q["description"] = "Edited in the console"
+							if err := srv.Put(fakeapi.Queries, q); err != nil {
+								t.Fatal(err)
+							}
+						}),
+					},
+				},
+				ExpectError: regexp.MustCompile(`Unable to update query: modified outside Terraform`),
+			},
+		},
+	})
+}
+
+// testAccEditBeforeApply is a plan check that runs edit once the plan is made, before it is
+// applied.
+type testAccEditBeforeApply func()
+
+func (e testAccEditBeforeApply) CheckPlan(ctx context.Context, req plancheck.CheckPlanRequest, resp *plancheck.CheckPlanResponse) {
+	e()
+}
+
+func TestAccQueryResource_disappears(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccQueryConfig,
+				Check:  testAccCheckExists(srv, fakeapi.Queries, "baselime_query.test"),
+			},
+			// Deleting the query outside Terraform plans a re-create
+			{
+				PreConfig: func() { srv.Delete(fakeapi.Queries, "acc-query") },
+				Config:    testAccProviderConfig(srv) + testAccQueryConfig,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("baselime_query.test", plancheck.ResourceActionCreate)},
+				},
+				Check: testAccCheckExists(srv, fakeapi.Queries, "baselime_query.test"),
+			},
+			// Deleting the query outside Terraform between refresh and destroy
+			{
+				Config:  testAccProviderConfig(srv) + testAccQueryConfig,
+				Destroy: true,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("baselime_query.test", plancheck.ResourceActionDestroy),
+						testAccEditBeforeApply(func() { srv.Delete(fakeapi.Queries, "acc-query") }),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccQueryResource_defaults(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Optional fields of the nested attributes can be left out
+			{
+				Config: testAccProviderConfig(srv) + `
+resource "baselime_query" "test" {
+  name        = "acc-query"
+  description = "Acceptance test query"
+  datasets    = ["lambda-logs"]
+  filters = [
+    {
+      key       = "error"
+      operation = "EXISTS"
+    }
+  ]
+  calculations = [
+    {
+      operator = "COUNT"
+    }
+  ]
+  group_by = [
+    {
+      value = "message"
+    }
+  ]
+  order_by = {
+    value = "COUNT"
+  }
+  needle = {
+    value = "error"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.0.value", ""),
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.0.type", "string"),
+					resource.TestCheckResourceAttr("baselime_query.test", "calculations.0.key", ""),
+					resource.TestCheckResourceAttr("baselime_query.test", "group_by.0.type", "string"),
+					resource.TestCheckResourceAttr("baselime_query.test", "order_by.order", "DESC"),
+					resource.TestCheckResourceAttr("baselime_query.test", "needle.is_regex", "false"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+		},
+	})
+}
+
+func TestAccQueryResource_validation(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	for _, tt := range []struct {
+		old, new string
+		err      string
+	}{
+		{`operation = "INCLUDES"`, `operation = "CONTAINS"`, `filters\[0\]\.operation value must be one of`},
+		{`type      = "string"`, `type      = "text"`, `filters\[0\]\.type value must be one of`},
+		{`operator = "COUNT"`, `operator = "TOTAL"`, `calculations\[0\]\.operator value must be one of`},
+		{`order = "DESC"`, `order = "DOWN"`, `order_by\.order value must be one of`},
+		{`filter_combination = "AND"`, `filter_combination = "XOR"`, `filter_combination value must be one of`},
+	} {
+		t.Run(tt.new, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config:      testAccProviderConfig(srv) + strings.Replace(testAccQueryConfig, tt.old, tt.new, 1),
+						PlanOnly:    true,
+						ExpectError: regexp.MustCompile(tt.err),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccQueryResource_validateConfig(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	groupBy := `  group_by = [
+    {
+      type  = "string"
+      value = "message"
+    }
+  ]
+`
+	for _, tt := range []struct {
+		name    string
+		replace []string
+		err     string
+	}{
+		{"count with key", []string{`key      = ""`, `key      = "duration"`}, `Invalid Calculation`},
+		{"sum without key", []string{`operator = "COUNT"`, `operator = "SUM"`}, `Invalid Calculation`},
+		{"rate with key", []string{`key      = ""`, `key      = "duration"`, `operator = "COUNT"`, `operator = "RATE"`}, `(?s)Invalid Calculation.*RATE calculation is the number of matching events`},
+		{"percentile without parameter", []string{`key      = ""`, `key      = "duration"`, `operator = "COUNT"`, `operator = "PERCENTILE"`}, `(?s)Invalid Calculation.*needs a parameter`},
+		{"percentile out of range", []string{`key      = ""`, `key      = "duration"`, `operator = "COUNT"`, `operator = "PERCENTILE"
+      parameter = 100`}, `(?s)Invalid Calculation.*PERCENTILE calculation must be greater.*got: 100`},
+		{"parameter of COUNT", []string{`operator = "COUNT"`, `operator = "COUNT"
+      parameter = 1`}, `(?s)Invalid Calculation.*COUNT calculation takes no parameter`},
+		{"order by unknown alias", []string{`value = "count"`, `value = "p99"`}, `Invalid Order By`},
+		{"order by field without group by", []string{`value = "count"`, `value = "level"`, groupBy, ``}, `Invalid Order By`},
+		{"invalid needle regex", []string{`value      = "error"
+    is_regex   = false`, `value      = "(error"
+    is_regex   = true`}, `Invalid Regular Expression`},
+		{"value not a number", []string{`type      = "string"`, `type      = "number"`}, `(?s)Invalid Filter Value.*"error" is not a number`},
+		{"value of IN", []string{`operation = "INCLUDES"`, `operation = "IN"`}, `(?s)Invalid Filter Value.*IN filters take a list of values`},
+		{"value of EXISTS", []string{`operation = "INCLUDES"`, `operation = "EXISTS"`}, `(?s)Invalid Filter Value.*EXISTS filters take no value`},
+		{"values of INCLUDES", []string{`value     = "error"`, `values    = ["error"]`}, `(?s)Invalid Filter Value.*values is only used by IN and NOT_IN`},
+		{"time range ending before it starts", []string{`limit = 10`, `limit = 10
+  time_range = {
+    from = "1h"
+    to   = "2h"
+  }`}, `Invalid Time Range`},
+		{"time range starting now", []string{`limit = 10`, `limit = 10
+  time_range = {
+    from = "0m"
+  }`}, `(?s)Invalid Time Range.*time_range.from "0m" must be longer than zero`},
+		{"granularity longer than the time range", []string{`limit = 10`, `limit = 10
+  time_range = {
+    from = "1h"
+  }
+  granularity = "2h"`}, `Invalid Granularity`},
+		{"invalid granularity", []string{`limit = 10`, `limit = 10
+  granularity = "1 hour"`}, `(?s)granularity.*must be a number followed by s, m, h or d`},
+		{"having unknown calculation", []string{`limit = 10`, `limit = 10
+  having = [
+    {
+      key       = "p99"
+      operation = ">"
+      value     = 100
+    }
+  ]`}, `Invalid Having`},
+		{"limit too low", []string{`limit = 10`, `limit = 0`}, `Invalid Limit`},
+		{"limit too high", []string{`limit = 10`, `limit = 100000`}, `Invalid Limit`},
+		{"parameters_json not an object", []string{`limit = 10`, `limit = 10
+  parameters_json = jsonencode(["sampling"])`}, `Invalid Parameters JSON`},
+		{"parameters_json setting typed parameters", []string{`limit = 10`, `limit = 10
+  parameters_json = jsonencode({ limit = 20, needle = { value = "warn", fuzzy = true } })`}, `(?s)Conflicting Parameters.*limit,\s+needle.value`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config:      testAccProviderConfig(srv) + strings.NewReplacer(tt.replace...).Replace(testAccQueryConfig),
+						PlanOnly:    true,
+						ExpectError: regexp.MustCompile(tt.err),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccQueryResource_where(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	config := func(where string) string {
+		return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "baselime_query" "test" {
+  name        = "acc-query"
+  description = "Acceptance test query"
+  datasets    = ["lambda-logs"]
+  where       = %q
+  calculations = [
+    {
+      operator = "COUNT"
+    }
+  ]
+}
+`, where)
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(srv, fakeapi.Queries, "baselime_query"),
+		Steps: []resource.TestStep{
+			// The expression is kept as written and sets the filters
+			{
+				Config: config("message includes 'error'  and @duration>500"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("baselime_query.test", "where", "message includes 'error'  and @duration>500"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.#", "2"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.0.key", "message"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.0.operation", "INCLUDES"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.0.value", "error"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.1.key", "@duration"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.1.type", "number"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filter_combination", "AND"),
+					func(*terraform.State) error {
+						q, _ := srv.Get(fakeapi.Queries, "acc-query")
+						params := q["parameters"].(map[string]interface{})
+						if params["filterCombination"] != "AND" || len(params["filters"].([]interface{})) != 2 {
+							return fmt.Errorf("unexpected filters in the API: %v", params)
+						}
+						return nil
+					},
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			// Changing the expression updates the filters
+			{
+				Config: config("level = 'error'"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.#", "1"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.0.key", "level"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply:             []plancheck.PlanCheck{plancheck.ExpectResourceAction("baselime_query.test", plancheck.ResourceActionUpdate)},
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			// Filters changed in the console show up as a canonical expression and are reverted
+			{
+				PreConfig: func() {
+					q, _ := srv.Get(fakeapi.Queries, "acc-query")
+					params := q["parameters"].(map[string]interface{})
+					params["filters"] = []interface{}{
+						map[string]interface{}{"key": "level", "operation": "!=", "value": "info", "type": "string"},
+					}
+					if err := srv.Put(fakeapi.Queries, q); err != nil {
+						t.Fatal(err)
+					}
+				},
+				RefreshState:       true,
+				ExpectNonEmptyPlan: true,
+				Check:              resource.TestCheckResourceAttr("baselime_query.test", "where", "level != 'info'"),
+			},
+			{
+				Config: config("level = 'error'"),
+				Check:  resource.TestCheckResourceAttr("baselime_query.test", "where", "level = 'error'"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectResourceAction("baselime_query.test", plancheck.ResourceActionUpdate)},
+				},
+			},
+			// Switching to filters
+			{
+				Config: testAccProviderConfig(srv) + testAccQueryConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("baselime_query.test", "where"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.0.key", "message"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+		},
+	})
+}
+
+func TestAccQueryResource_whereErrors(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	filters := `  filters = [
+    {
+      key       = "message"
+      operation = "INCLUDES"
+      value     = "error"
+      type      = "string"
+    }
+  ]
+`
+	for _, tt := range []struct {
+		name    string
+		replace []string
+		err     string
+	}{
+		{"syntax error", []string{filters, `  where = "message INCLUDES error"` + "\n", `filter_combination = "AND"`, ``}, `(?s)Invalid Filter Expression.*column 18`},
+		{"syntax error in a heredoc", []string{filters, "  where = <<-EOT\n    level = 'error' AND\n    message INCLUDES error\n  EOT\n", `filter_combination = "AND"`, ``},
+			`(?s)Invalid Filter Expression.*line 2, column 18:\s+message INCLUDES error\s+\^`},
+		{"where and filters", []string{`filter_combination = "AND"`, `where = "level = 'error'"`}, `Conflicting Filters`},
+		{"where and filter_combination", []string{filters, `  where = "level = 'error' OR level = 'warn'"` + "\n"}, `Conflicting Filter Combination`},
+		{"no filters", []string{filters, ``}, `Missing Filters`},
+		{"empty filter group", []string{filters, "  filter_group {\n  }\n"}, `Empty Filter Group`},
+		{"empty nested filter group", []string{filters, "  filter_group {\n    filter_group {\n    }\n  }\n"}, `Empty Filter Group`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config:      testAccProviderConfig(srv) + strings.NewReplacer(tt.replace...).Replace(testAccQueryConfig),
+						PlanOnly:    true,
+						ExpectError: regexp.MustCompile(tt.err),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccQueryResource_filterGroups(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	config := func(filters string) string {
+		return testAccProviderConfig(srv) + `
+resource "baselime_query" "test" {
+  name        = "acc-query"
+  description = "Acceptance test query"
+  datasets    = ["lambda-logs"]
+` + filters + `
+  calculations = [
+    {
+      operator = "COUNT"
+    }
+  ]
+}
+`
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(srv, fakeapi.Queries, "baselime_query"),
+		Steps: []resource.TestStep{
+			// (service = api OR service = worker) AND level = error
+			{
+				Config: config(`
+  where              = "level = 'error'"
+  filter_combination = "AND"
+  filter_group {
+    filters = [
+      {
+        key       = "service"
+        operation = "="
+        value     = "api"
+      },
+      {
+        key       = "service"
+        operation = "="
+        value     = "worker"
+      }
+    ]
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("baselime_query.test", "filter_combination", "AND"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filter_group.#", "1"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filter_group.0.filter_combination", "OR"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filter_group.0.filters.#", "2"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filter_group.0.filters.1.value", "worker"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filter_group.0.filters.1.type", "string"),
+					func(*terraform.State) error {
+						q, _ := srv.Get(fakeapi.Queries, "acc-query")
+						params := q["parameters"].(map[string]interface{})
+						got, _ := json.Marshal(params["filterGroups"])
+						want := `[{"filterCombination":"OR","filters":[` +
+							`{"key":"service","operation":"=","type":"string","value":"api"},` +
+							`{"key":"service","operation":"=","type":"string","value":"worker"}]}]`
+						if params["filterCombination"] != "AND" || string(got) != want {
+							return fmt.Errorf("unexpected filter groups in the API: %v", params)
+						}
+						return nil
+					},
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			// Nested groups only
+			{
+				Config: config(`
+  filter_group {
+    filter_combination = "AND"
+    filter_group {
+      filters = [
+        {
+          key       = "level"
+          operation = "="
+          value     = "error"
+        }
+      ]
+    }
+    filter_group {
+      filters = [
+        {
+          key       = "@duration"
+          operation = ">"
+          value     = "500"
+          type      = "number"
+        }
+      ]
+    }
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.#", "0"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filter_group.0.filter_combination", "AND"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filter_group.0.filter_group.#", "2"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filter_group.0.filter_group.1.filters.0.key", "@duration"),
+					func(*terraform.State) error {
+						q, _ := srv.Get(fakeapi.Queries, "acc-query")
+						groups := q["parameters"].(map[string]interface{})["filterGroups"].([]interface{})
+						nested, _ := groups[0].(map[string]interface{})["filterGroups"].([]interface{})
+						if len(groups) != 1 || len(nested) != 2 {
+							return fmt.Errorf("unexpected filter groups in the API: %v", groups)
+						}
+						return nil
+					},
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply:             []plancheck.PlanCheck{plancheck.ExpectResourceAction("baselime_query.test", plancheck.ResourceActionUpdate)},
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			{
+				ResourceName:                         "baselime_query.test",
+				ImportState:                          true,
+				ImportStateId:                        "acc-query",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+				ImportStateVerifyIgnore:              []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func TestAccQueryResource_filterValues(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(srv, fakeapi.Queries, "baselime_query"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+resource "baselime_query" "test" {
+  name        = "acc-query"
+  description = "Acceptance test query"
+  datasets    = ["lambda-logs"]
+  filters = [
+    {
+      key       = "@duration"
+      operation = ">"
+      value     = "500.0"
+      type      = "number"
+    },
+    {
+      key       = "cold_start"
+      operation = "="
+      value     = true
+      type      = "boolean"
+    },
+    {
+      key       = "status"
+      operation = "IN"
+      values    = [500, 502]
+      type      = "number"
+    },
+    {
+      key       = "region"
+      operation = "NOT_IN"
+      values    = ["eu-west-1,eu-west-2"]
+    },
+    {
+      key       = "error"
+      operation = "EXISTS"
+    }
+  ]
+  filter_combination = "AND"
+  calculations = [
+    {
+      operator = "COUNT"
+    }
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.0.value", "500.0"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.1.value", "true"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.2.value", ""),
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.2.values.#", "2"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.2.values.1", "502"),
+					resource.TestCheckResourceAttr("baselime_query.test", "filters.3.values.0", "eu-west-1,eu-west-2"),
+					func(*terraform.State) error {
+						q, _ := srv.Get(fakeapi.Queries, "acc-query")
+						got := q["parameters"].(map[string]interface{})["filters"]
+						wantJSON := `[{"key":"@duration","operation":">","type":"number","value":500},` +
+							`{"key":"cold_start","operation":"=","type":"boolean","value":true},` +
+							`{"key":"status","operation":"IN","type":"number","value":[500,502]},` +
+							`{"key":"region","operation":"NOT_IN","type":"string","value":["eu-west-1,eu-west-2"]},` +
+							`{"key":"error","operation":"EXISTS","type":"string"}]`
+						var want interface{}
+						_ = json.Unmarshal([]byte(wantJSON), &want)
+						if !reflect.DeepEqual(got, want) {
+							return fmt.Errorf("unexpected filters in the API: %v", got)
+						}
+						return nil
+					},
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+		},
+	})
+}
+
+func TestAccQueryResource_filterValuesUnknown(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// values that come from another resource are only checked at apply
+			{
+				Config: testAccProviderConfig(srv) + `
+resource "terraform_data" "status" {
+  input = "server-error"
+}
+
+resource "baselime_query" "test" {
+  name        = "acc-query"
+  description = "Acceptance test query"
+  datasets    = ["lambda-logs"]
+  filters = [
+    {
+      key       = "status"
+      operation = "IN"
+      values    = [500, terraform_data.status.output]
+      type      = "number"
+    }
+  ]
+  calculations = [
+    {
+      operator = "COUNT"
+    }
+  ]
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)Invalid Filter Value.*values\[1\]: "server-error" is not a number`),
+			},
+		},
+	})
+}
+
+func TestAccQueryResource_calculations(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(srv, fakeapi.Queries, "baselime_query"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+resource "baselime_query" "test" {
+  name        = "acc-query"
+  description = "Acceptance test query"
+  datasets    = ["lambda-logs"]
+  where       = "message INCLUDES 'error'"
+  calculations = [
+    {
+      key      = "@duration"
+      operator = "P50"
+    },
+    {
+      key       = "@duration"
+      operator  = "PERCENTILE"
+      parameter = 99.9
+      alias     = "p999"
+    },
+    {
+      operator  = "RATE"
+      parameter = 60
+    },
+    {
+      key      = "requestId"
+      operator = "COUNT_DISTINCT"
+    }
+  ]
+  order_by = {
+    value = "p999"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("baselime_query.test", "calculations.0.parameter"),
+					resource.TestCheckResourceAttr("baselime_query.test", "calculations.1.parameter", "99.9"),
+					resource.TestCheckResourceAttr("baselime_query.test", "calculations.2.parameter", "60"),
+					func(*terraform.State) error {
+						q, _ := srv.Get(fakeapi.Queries, "acc-query")
+						got := q["parameters"].(map[string]interface{})["calculations"]
+						wantJSON := `[{"key":"@duration","operator":"P50"},` +
+							`{"key":"@duration","operator":"PERCENTILE","parameter":99.9,"alias":"p999"},` +
+							`{"operator":"RATE","parameter":60},` +
+							`{"key":"requestId","operator":"COUNT_DISTINCT"}]`
+						var want interface{}
+						_ = json.Unmarshal([]byte(wantJSON), &want)
+						if !reflect.DeepEqual(got, want) {
+							return fmt.Errorf("unexpected calculations in the API: %v", got)
+						}
+						return nil
+					},
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+		},
+	})
+}
+
+func TestAccQueryResource_timeRange(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	// the alert on the query is unaffected by the time range of the query
+	config := testAccProviderConfig(srv) + strings.Replace(testAccAlertConfig("5m", "email"), "  limit = 10\n", `  limit = 10
+  time_range = {
+    from = "24h"
+    to   = "1h"
+  }
+  granularity = "5m"
+  having = [
+    {
+      key       = "count"
+      operation = ">"
+      value     = 100
+    }
+  ]
+`, 1)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(srv, fakeapi.Queries, "baselime_query"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("baselime_query.test", "time_range.from", "24h"),
+					resource.TestCheckResourceAttr("baselime_query.test", "time_range.to", "1h"),
+					resource.TestCheckResourceAttr("baselime_query.test", "granularity", "5m"),
+					resource.TestCheckResourceAttr("baselime_query.test", "having.0.value", "100"),
+					testAccCheckExists(srv, fakeapi.Alerts, "baselime_alert.test"),
+					func(*terraform.State) error {
+						q, _ := srv.Get(fakeapi.Queries, "acc-query")
+						params := q["parameters"].(map[string]interface{})
+						var want map[string]interface{}
+						_ = json.Unmarshal([]byte(`{
+							"timeRange": {"from": "24h", "to": "1h"},
+							"granularity": "5m",
+							"having": [{"key": "count", "operation": ">", "value": 100}]
+						}`), &want)
+						for name, value := range want {
+							if !reflect.DeepEqual(params[name], value) {
+								return fmt.Errorf("unexpected %s in the API: %v", name, params[name])
+							}
+						}
+						return nil
+					},
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			// Removing them updates the query only
+			{
+				Config: testAccProviderConfig(srv) + testAccAlertConfig("5m", "email"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("baselime_query.test", "time_range"),
+					resource.TestCheckNoResourceAttr("baselime_query.test", "granularity"),
+					resource.TestCheckNoResourceAttr("baselime_query.test", "having"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("baselime_query.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectResourceAction("baselime_alert.test", plancheck.ResourceActionNoop),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+		},
+	})
+}
+
+func TestAccQueryResource_parametersJSON(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	withParametersJSON := func(parametersJSON string) string {
+		return testAccProviderConfig(srv) + strings.Replace(testAccQueryConfig, "  limit = 10\n", "  limit = 10\n"+parametersJSON, 1)
+	}
+	// the API returns the parameters in another order and format
+	config := withParametersJSON(`  parameters_json = <<-EOT
+    { "sampling": { "rate": 0.50 },
+      "needle": { "fuzzy": true } }
+  EOT
+`)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(srv, fakeapi.Queries, "baselime_query"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("baselime_query.test", "parameters_json", `{ "sampling": { "rate": 0.50 },
+  "needle": { "fuzzy": true } }
+`),
+					func(*terraform.State) error {
+						q, _ := srv.Get(fakeapi.Queries, "acc-query")
+						params := q["parameters"].(map[string]interface{})
+						var want map[string]interface{}
+						_ = json.Unmarshal([]byte(`{
+							"sampling": {"rate": 0.5},
+							"needle": {"value": "error", "fuzzy": true},
+							"limit": 10
+						}`), &want)
+						for name, value := range want {
+							if !reflect.DeepEqual(params[name], value) {
+								return fmt.Errorf("unexpected %s in the API: %v", name, params[name])
+							}
+						}
+						return nil
+					},
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			// A parameter changed outside Terraform is planned back
+			{
+				PreConfig: func() {
+					q, _ := srv.Get(fakeapi.Queries, "acc-query")
+					q["parameters"].(map[string]interface{})["sampling"] = map[string]interface{}{"rate": 0.25}
+					if err := srv.Put(fakeapi.Queries, q); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("baselime_query.test", plancheck.ResourceActionUpdate),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			// Parameters set by the API only are not part of parameters_json
+			{
+				PreConfig: func() {
+					q, _ := srv.Get(fakeapi.Queries, "acc-query")
+					q["parameters"].(map[string]interface{})["version"] = 2
+					if err := srv.Put(fakeapi.Queries, q); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			// Removing it removes the parameters from the query
+			{
+				Config: withParametersJSON(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("baselime_query.test", "parameters_json"),
+					func(*terraform.State) error {
+						q, _ := srv.Get(fakeapi.Queries, "acc-query")
+						params := q["parameters"].(map[string]interface{})
+						if _, ok := params["sampling"]; ok {
+							return fmt.Errorf("unexpected sampling in the API: %v", params["sampling"])
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccQueryResource_rename(t *testing.T) {
+	srv := testAccFakeAPI(t)
+	renamed := strings.Replace(testAccAlertConfig("5m", "email"), `"acc-query"`, `"acc-query-renamed"`, 1)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + testAccAlertConfig("5m", "email"),
+				Check:  testAccCheckExists(srv, fakeapi.Queries, "baselime_query.test"),
+			},
+			// Renaming the query replaces it and re-points the alert
+			{
+				Config: testAccProviderConfig(srv) + renamed,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("baselime_query.test", plancheck.ResourceActionDestroyBeforeCreate),
+						plancheck.ExpectResourceAction("baselime_alert.test", plancheck.ResourceActionUpdate),
+						expectPlannedValue("baselime_query.test", "id", "acc-query-renamed"),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("baselime_query.test", "id", "acc-query-renamed"),
+					resource.TestCheckResourceAttr("baselime_alert.test", "query", "acc-query-renamed"),
+					func(*terraform.State) error {
+						if _, ok := srv.Get(fakeapi.Queries, "acc-query"); ok {
+							return fmt.Errorf("the old query still exists in the API")
+						}
+						a, _ := srv.Get(fakeapi.Alerts, "acc-alert")
+						if q := a["parameters"].(map[string]interface{})["queryId"]; q != "acc-query-renamed" {
+							return fmt.Errorf("the alert was not re-pointed to the new query: %v", q)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// expectPlannedValue is a plan check that the attribute of a resource is planned with a
+// known value.
+func expectPlannedValue(resourceAddress, attribute, value string) plancheck.PlanCheck {
+	return plannedValueCheck{resourceAddress, attribute, value}
+}
+
+type plannedValueCheck struct {
+	resourceAddress, attribute, value string
+}
+
+func (c plannedValueCheck) CheckPlan(ctx context.Context, req plancheck.CheckPlanRequest, resp *plancheck.CheckPlanResponse) {
+	for _, rc := range req.Plan.ResourceChanges {
+		if rc.Address != c.resourceAddress {
+			continue
+		}
+		after, _ := rc.Change.After.(map[string]interface{})
+		if after[c.attribute] != c.value {
+			resp.Error = fmt.Errorf("%s.%s is planned as %v, expected %q", c.resourceAddress, c.attribute, after[c.attribute], c.value)
+		}
+		return
+	}
+	resp.Error = fmt.Errorf("%s not found in plan", c.resourceAddress)
+}
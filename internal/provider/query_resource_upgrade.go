@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func (r *QueryResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	schemaV0 := queryResourceSchemaV0(ctx)
+	return map[int64]resource.StateUpgrader{
+		// Version 1 declares filters, calculations, group_by, order_by and needle as
+		// nested attributes. Their values are stored the same way, so the state is kept
+		// as it is, with the attributes added since then null.
+		0: {
+			PriorSchema: &schemaV0,
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				state, err := withNullAttributes(req.State.Raw, resp.State.Schema.Type().TerraformType(ctx))
+				if err != nil {
+					resp.Diagnostics.AddError("Unable to Upgrade Resource State", "Unable to read the prior state: "+err.Error())
+					return
+				}
+				resp.State.Raw = state
+			},
+		},
+	}
+}
+
+// withNullAttributes returns v as a value of typ, which has the same attributes as the
+// type of v or more, at any depth. The attributes v does not have are null.
+func withNullAttributes(v tftypes.Value, typ tftypes.Type) (tftypes.Value, error) {
+	switch {
+	case v.IsNull():
+		return tftypes.NewValue(typ, nil), nil
+	case !v.IsKnown():
+		return tftypes.NewValue(typ, tftypes.UnknownValue), nil
+	}
+	switch typ := typ.(type) {
+	case tftypes.Object:
+		var attrs map[string]tftypes.Value
+		if err := v.As(&attrs); err != nil {
+			return tftypes.Value{}, err
+		}
+		values := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+		for name, attrType := range typ.AttributeTypes {
+			attr, ok := attrs[name]
+			if !ok {
+				values[name] = tftypes.NewValue(attrType, nil)
+				continue
+			}
+			value, err := withNullAttributes(attr, attrType)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			values[name] = value
+		}
+		return tftypes.NewValue(typ, values), nil
+	case tftypes.List:
+		elems, err := elementsWithNullAttributes(v, typ.ElementType)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		return tftypes.NewValue(typ, elems), nil
+	case tftypes.Set:
+		elems, err := elementsWithNullAttributes(v, typ.ElementType)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		return tftypes.NewValue(typ, elems), nil
+	}
+	return v, nil
+}
+
+func elementsWithNullAttributes(v tftypes.Value, elemType tftypes.Type) ([]tftypes.Value, error) {
+	var elems []tftypes.Value
+	if err := v.As(&elems); err != nil {
+		return nil, err
+	}
+	for i, elem := range elems {
+		value, err := withNullAttributes(elem, elemType)
+		if err != nil {
+			return nil, err
+		}
+		elems[i] = value
+	}
+	return elems, nil
+}
+
+// queryResourceSchemaV0 is the schema of baselime_query before version 1, where the
+// nested fields were declared as object types, and before the computed metadata and the
+// timeouts were added.
+func queryResourceSchemaV0(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		MarkdownDescription: "Query resource",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Query name",
+			},
+			"description": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Query description",
+			},
+			"datasets": schema.ListAttribute{
+				Required:            true,
+				MarkdownDescription: "Query datasets",
+				ElementType:         types.StringType,
+			},
+			"filters": schema.ListAttribute{
+				Required:            true,
+				MarkdownDescription: "Query filters",
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"key":       types.StringType,
+						"operation": types.StringType,
+						"value":     types.StringType,
+						"type":      types.StringType,
+					},
+				},
+			},
+			"filter_combination": schema.StringAttribute{
+				Optional:            true,
+				Default:             stringdefault.StaticString("OR"),
+				MarkdownDescription: "Query filter combination",
+				Computed:            true,
+			},
+			"calculations": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "Query calculations",
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"key":      types.StringType,
+						"operator": types.StringType,
+						"alias":    types.StringType,
+					},
+				},
+			},
+			"group_by": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "Query group by",
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"type":  types.StringType,
+						"value": types.StringType,
+					},
+				},
+			},
+			"order_by": schema.ObjectAttribute{
+				Optional: true,
+				AttributeTypes: map[string]attr.Type{
+					"value": types.StringType,
+					"order": types.StringType,
+				},
+			},
+			"limit": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Query limit",
+				Default:             int64default.StaticInt64(50),
+				Computed:            true,
+			},
+			"needle": schema.ObjectAttribute{
+				Optional: true,
+				AttributeTypes: map[string]attr.Type{
+					"value":      types.StringType,
+					"is_regex":   types.BoolType,
+					"match_case": types.BoolType,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"testing"
+)
+
+func TestQueryResource_UpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	querySchema := schemaResp.ResourceSchemas["baselime_query"]
+	if querySchema.Version != 1 {
+		t.Fatalf("schema version = %d, want 1", querySchema.Version)
+	}
+
+	// state written by version 0, before the computed metadata attributes and the timeouts existed
+	resp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: "baselime_query",
+		Version:  0,
+		RawState: &tfprotov6.RawState{JSON: []byte(`{
+			"name": "errors",
+			"description": "Errors",
+			"datasets": ["lambda-logs"],
+			"filters": [{"key": "level", "operation": "=", "value": "error", "type": "string"}],
+			"filter_combination": "AND",
+			"calculations": [{"key": "", "operator": "COUNT", "alias": "count"}],
+			"group_by": null,
+			"order_by": {"value": "count", "order": "DESC"},
+			"limit": 10,
+			"needle": null
+		}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Errorf("%s: %s", d.Summary, d.Detail)
+	}
+	state, err := resp.UpgradedState.Unmarshal(querySchema.ValueType())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attr := func(steps ...tftypes.AttributePathStep) interface{} {
+		v, _, err := tftypes.WalkAttributePath(state, tftypes.NewAttributePathWithSteps(steps))
+		if err != nil {
+			t.Fatalf("%v: %s", steps, err)
+		}
+		return v
+	}
+	for _, tt := range []struct {
+		path []tftypes.AttributePathStep
+		want tftypes.Value
+	}{
+		{[]tftypes.AttributePathStep{tftypes.AttributeName("name")}, tftypes.NewValue(tftypes.String, "errors")},
+		{[]tftypes.AttributePathStep{tftypes.AttributeName("id")}, tftypes.NewValue(tftypes.String, nil)},
+		{[]tftypes.AttributePathStep{tftypes.AttributeName("filters"), tftypes.ElementKeyInt(0), tftypes.AttributeName("operation")}, tftypes.NewValue(tftypes.String, "=")},
+		{[]tftypes.AttributePathStep{tftypes.AttributeName("filters"), tftypes.ElementKeyInt(0), tftypes.AttributeName("values")}, tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil)},
+		{[]tftypes.AttributePathStep{tftypes.AttributeName("where")}, tftypes.NewValue(tftypes.String, nil)},
+		{[]tftypes.AttributePathStep{tftypes.AttributeName("calculations"), tftypes.ElementKeyInt(0), tftypes.AttributeName("alias")}, tftypes.NewValue(tftypes.String, "count")},
+		{[]tftypes.AttributePathStep{tftypes.AttributeName("order_by"), tftypes.AttributeName("order")}, tftypes.NewValue(tftypes.String, "DESC")},
+		{[]tftypes.AttributePathStep{tftypes.AttributeName("limit")}, tftypes.NewValue(tftypes.Number, 10)},
+	} {
+		got := attr(tt.path...)
+		if !tt.want.Equal(got.(tftypes.Value)) {
+			t.Errorf("%v = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	if timeouts := attr(tftypes.AttributeName("timeouts")).(tftypes.Value); !timeouts.IsNull() {
+		t.Errorf("timeouts = %v, want null", timeouts)
+	}
+}
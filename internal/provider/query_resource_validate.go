@@ -0,0 +1,294 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/baselime/terraform-provider-baselime/internal/filterexpr"
+	"github.com/baselime/terraform-provider-baselime/internal/models"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var _ resource.ResourceWithValidateConfig = &QueryResource{}
+
+// ValidateConfig checks the parts of a query that depend on each other, which the API
+// would otherwise only reject when the query is created or updated. Values that are not
+// known until apply are not checked.
+func (r *QueryResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var where, filterCombination types.String
+	var filters types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("where"), &where)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("filters"), &filters)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("filter_combination"), &filterCombination)...)
+	filterGroups, filterGroupsKnown := knownListObjects(ctx, req.Config, path.Root("filter_group"), &resp.Diagnostics)
+	// the combination of the where expression, if it has several conditions
+	whereCombination := ""
+	if !where.IsNull() && !where.IsUnknown() {
+		_, combination, err := filterexpr.Parse(where.ValueString())
+		if err != nil {
+			addFilterExpressionError(&resp.Diagnostics, where.ValueString(), err)
+		}
+		whereCombination = combination
+	}
+	switch {
+	case where.IsNull() && filters.IsNull() && filterGroupsKnown && len(filterGroups) == 0:
+		resp.Diagnostics.AddAttributeError(path.Root("filters"), "Missing Filters",
+			"Either filters, where or a filter_group must be set.")
+	case !where.IsNull() && !filters.IsNull():
+		resp.Diagnostics.AddAttributeError(path.Root("where"), "Conflicting Filters",
+			"where cannot be set together with filters, as it sets the filters of the query.")
+	case whereCombination != "" && !filterCombination.IsNull():
+		resp.Diagnostics.AddAttributeError(path.Root("filter_combination"), "Conflicting Filter Combination",
+			fmt.Sprintf("filter_combination cannot be set together with where, whose conditions are joined by %s.", whereCombination))
+	}
+	validateFilters(ctx, req.Config, path.Root("filters"), &resp.Diagnostics)
+	validateFilterGroups(ctx, req.Config, path.Root("filter_group"), &resp.Diagnostics)
+
+	// names the results of the query can be ordered by; nil if some are not known yet
+	calculationNames := map[string]bool{}
+	calculations, known := knownListObjects(ctx, req.Config, path.Root("calculations"), &resp.Diagnostics)
+	if !known {
+		calculationNames = nil
+	}
+	for i, obj := range calculations {
+		p := path.Root("calculations").AtListIndex(i)
+		var calc models.QueryCalculation
+		if obj.IsUnknown() || !knownObject(ctx, obj, &calc, &resp.Diagnostics) {
+			calculationNames = nil
+			continue
+		}
+		if calc.Operator.IsUnknown() || calc.Key.IsUnknown() || calc.Alias.IsUnknown() {
+			calculationNames = nil
+			continue
+		}
+		operator, key := calc.Operator.ValueString(), calc.Key.ValueString()
+		if op, ok := models.LookupQueryCalculationOperator(operator); ok {
+			validateCalculation(op, key, calc.Parameter, p, &resp.Diagnostics)
+		}
+		if calculationNames != nil {
+			calculationNames[operator] = true
+			if alias := calc.Alias.ValueString(); alias != "" {
+				calculationNames[alias] = true
+			}
+		}
+	}
+
+	// fields the query is grouped by; nil if some are not known yet
+	groups := map[string]bool{}
+	groupBy, groupsKnown := knownListObjects(ctx, req.Config, path.Root("group_by"), &resp.Diagnostics)
+	if !groupsKnown {
+		groups = nil
+	}
+	for _, obj := range groupBy {
+		var group models.QueryGroupBy
+		if obj.IsUnknown() || !knownObject(ctx, obj, &group, &resp.Diagnostics) || group.Value.IsUnknown() {
+			groups = nil
+			break
+		}
+		groups[group.Value.ValueString()] = true
+	}
+
+	var orderBy types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("order_by"), &orderBy)...)
+	var order models.QueryOrderBy
+	if !orderBy.IsNull() && !orderBy.IsUnknown() && knownObject(ctx, orderBy, &order, &resp.Diagnostics) &&
+		!order.Value.IsUnknown() && calculationNames != nil && groups != nil {
+		value := order.Value.ValueString()
+		if !calculationNames[value] && !groups[value] {
+			detail := fmt.Sprintf("order_by.value %q must be the alias or the operator of one of the calculations, or a field the query is grouped by.", value)
+			if len(groups) == 0 {
+				detail += fmt.Sprintf(" To order the results by the field %q, also add it to group_by.", value)
+			}
+			resp.Diagnostics.AddAttributeError(path.Root("order_by").AtName("value"), "Invalid Order By", detail)
+		}
+	}
+
+	havings, _ := knownListObjects(ctx, req.Config, path.Root("having"), &resp.Diagnostics)
+	for i, obj := range havings {
+		var having models.QueryHaving
+		if obj.IsUnknown() || !knownObject(ctx, obj, &having, &resp.Diagnostics) || having.Key.IsUnknown() || calculationNames == nil {
+			continue
+		}
+		if key := having.Key.ValueString(); !calculationNames[key] {
+			resp.Diagnostics.AddAttributeError(path.Root("having").AtListIndex(i).AtName("key"), "Invalid Having",
+				fmt.Sprintf("having.key %q must be the alias or the operator of one of the calculations.", key))
+		}
+	}
+
+	validateTimeRange(ctx, req.Config, &resp.Diagnostics)
+
+	var needle types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("needle"), &needle)...)
+	var search models.SearchNeedle
+	if !needle.IsNull() && !needle.IsUnknown() && knownObject(ctx, needle, &search, &resp.Diagnostics) &&
+		search.IsRegex.ValueBool() && !search.Value.IsUnknown() {
+		if _, err := regexp.Compile(search.Value.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("needle").AtName("value"), "Invalid Regular Expression",
+				fmt.Sprintf("needle.value must be a valid regular expression when is_regex is true: %s", err))
+		}
+	}
+
+	var limit types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("limit"), &limit)...)
+	if !limit.IsNull() && !limit.IsUnknown() {
+		if v := limit.ValueInt64(); v < models.QueryMinLimit || v > models.QueryMaxLimit {
+			resp.Diagnostics.AddAttributeError(path.Root("limit"), "Invalid Limit",
+				fmt.Sprintf("limit must be between %d and %d, got: %d", models.QueryMinLimit, models.QueryMaxLimit, v))
+		}
+	}
+}
+
+// validateTimeRange checks that the time range of a query ends after it starts, and that
+// the granularity is shorter than it. Durations in the wrong format are reported by the
+// schema validators.
+func validateTimeRange(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) {
+	var timeRange types.Object
+	var granularity types.String
+	diags.Append(config.GetAttribute(ctx, path.Root("time_range"), &timeRange)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("granularity"), &granularity)...)
+	var tr models.QueryTimeRange
+	if timeRange.IsNull() || timeRange.IsUnknown() || !knownObject(ctx, timeRange, &tr, diags) ||
+		tr.From.IsUnknown() || tr.To.IsUnknown() {
+		return
+	}
+	from, err := models.ParseQueryDuration(tr.From.ValueString())
+	if err != nil {
+		return
+	}
+	var to time.Duration
+	if !tr.To.IsNull() {
+		if to, err = models.ParseQueryDuration(tr.To.ValueString()); err != nil {
+			return
+		}
+	}
+	if tr.To.IsNull() && from == 0 {
+		diags.AddAttributeError(path.Root("time_range").AtName("from"), "Invalid Time Range",
+			fmt.Sprintf("time_range.from %q must be longer than zero, for the time range to start before now.", tr.From.ValueString()))
+		return
+	}
+	if to >= from {
+		diags.AddAttributeError(path.Root("time_range").AtName("to"), "Invalid Time Range",
+			fmt.Sprintf("time_range.to %q must be shorter than time_range.from %q, for the time range to end after it starts.", tr.To.ValueString(), tr.From.ValueString()))
+		return
+	}
+	if granularity.IsNull() || granularity.IsUnknown() {
+		return
+	}
+	if g, err := models.ParseQueryDuration(granularity.ValueString()); err == nil && g >= from-to {
+		diags.AddAttributeError(path.Root("granularity"), "Invalid Granularity",
+			fmt.Sprintf("granularity %q must be shorter than the time range, for the results to have more than one bucket.", granularity.ValueString()))
+	}
+}
+
+// validateCalculation checks the key and the parameter of a calculation at p against its
+// operator.
+func validateCalculation(op models.QueryCalculationOperator, key string, parameter types.Float64, p path.Path, diags *diag.Diagnostics) {
+	switch {
+	case op.Name == "COUNT" && key != "":
+		diags.AddAttributeError(p.AtName("key"), "Invalid Calculation",
+			"A COUNT calculation counts the matching events and takes no key. Remove the key, or use COUNT_DISTINCT to count the distinct values of a field.")
+	case !op.NeedsKey && key != "":
+		diags.AddAttributeError(p.AtName("key"), "Invalid Calculation",
+			fmt.Sprintf("A %s calculation is %s and takes no key. Remove the key.", op.Name, op.Description))
+	case op.NeedsKey && key == "":
+		diags.AddAttributeError(p.AtName("key"), "Invalid Calculation",
+			fmt.Sprintf("A %s calculation needs the key of the field it is calculated on.", op.Name))
+	}
+
+	if parameter.IsUnknown() {
+		return
+	}
+	switch {
+	case op.Parameter == models.CalculationParameterNone && !parameter.IsNull():
+		diags.AddAttributeError(p.AtName("parameter"), "Invalid Calculation",
+			fmt.Sprintf("A %s calculation takes no parameter. Remove the parameter.", op.Name))
+	case op.Parameter == models.CalculationParameterRequired && parameter.IsNull():
+		diags.AddAttributeError(p.AtName("parameter"), "Invalid Calculation",
+			fmt.Sprintf("A %s calculation needs a parameter: %s.", op.Name, op.Description))
+	case !parameter.IsNull():
+		if v := parameter.ValueFloat64(); v <= op.ParameterMin || v >= op.ParameterMax {
+			diags.AddAttributeError(p.AtName("parameter"), "Invalid Calculation",
+				fmt.Sprintf("The parameter of a %s calculation must be greater than %g and less than %g, got: %g", op.Name, op.ParameterMin, op.ParameterMax, v))
+		}
+	}
+}
+
+// validateFilters checks the values of the filters at p against their operation and
+// their type.
+func validateFilters(ctx context.Context, config tfsdk.Config, p path.Path, diags *diag.Diagnostics) {
+	filters, _ := knownListObjects(ctx, config, p, diags)
+	for i, obj := range filters {
+		var filter models.QueryFilter
+		if obj.IsUnknown() || !knownObject(ctx, obj, &filter, diags) {
+			continue
+		}
+		if _, err := filter.ToApiModel(); err != nil {
+			diags.AddAttributeError(p.AtListIndex(i), "Invalid Filter Value", err.Error())
+		}
+	}
+}
+
+// validateFilterGroups checks that the filter groups at p, and the groups nested in them,
+// are not empty, and the values of their filters.
+func validateFilterGroups(ctx context.Context, config tfsdk.Config, p path.Path, diags *diag.Diagnostics) {
+	groups, _ := knownListObjects(ctx, config, p, diags)
+	for i, group := range groups {
+		if group.IsUnknown() {
+			continue
+		}
+		groupPath := p.AtListIndex(i)
+		validateFilters(ctx, config, groupPath.AtName("filters"), diags)
+		filters, _ := group.Attributes()["filters"].(types.List)
+		nested, hasNested := group.Attributes()["filter_group"].(types.List)
+		if filters.IsNull() && (!hasNested || (!nested.IsUnknown() && len(nested.Elements()) == 0)) {
+			diags.AddAttributeError(groupPath, "Empty Filter Group",
+				"A filter_group needs filters or a nested filter_group.")
+		}
+		if hasNested {
+			validateFilterGroups(ctx, config, groupPath.AtName("filter_group"), diags)
+		}
+	}
+}
+
+// addFilterExpressionError reports an error in the where expression of a query, pointing
+// at its position in the line of the expression that has it.
+func addFilterExpressionError(diags *diag.Diagnostics, expr string, err error) {
+	detail := err.Error()
+	var syntaxErr *filterexpr.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line := strings.TrimRight(strings.Split(expr, "\n")[syntaxErr.Line-1], "\r")
+		detail = fmt.Sprintf("%s:\n\n    %s\n    %s^", err, line, strings.Repeat(" ", syntaxErr.Column-1))
+	}
+	diags.AddAttributeError(path.Root("where"), "Invalid Filter Expression", detail)
+}
+
+// knownListObjects returns the elements of the list of objects at p in config, and
+// whether the list is known. A null list has no elements.
+func knownListObjects(ctx context.Context, config tfsdk.Config, p path.Path, diags *diag.Diagnostics) ([]types.Object, bool) {
+	var list types.List
+	diags.Append(config.GetAttribute(ctx, p, &list)...)
+	if list.IsUnknown() {
+		return nil, false
+	}
+	if list.IsNull() {
+		return nil, true
+	}
+	var objects []types.Object
+	diags.Append(list.ElementsAs(ctx, &objects, false)...)
+	return objects, true
+}
+
+// knownObject reads obj into target, whose fields must handle unknown values.
+func knownObject(ctx context.Context, obj types.Object, target interface{}, diags *diag.Diagnostics) bool {
+	objDiags := obj.As(ctx, target, basetypes.ObjectAsOptions{})
+	diags.Append(objDiags...)
+	return !objDiags.HasError()
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// requiresReplaceOnRename forces the replacement of a resource whose name changes. The
+// name of a query, alert or dashboard is its ID in the API, which has no rename
+// operation: updating the object under its new name would create a second one and leave
+// the old one behind. kind is the singular object name used in descriptions.
+func requiresReplaceOnRename(kind string) planmodifier.String {
+	description := fmt.Sprintf("The name is the ID of the %s in the Baselime API; changing it destroys the %s and creates a new one.", kind, kind)
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			if req.StateValue.IsNull() || req.PlanValue.IsUnknown() {
+				return
+			}
+			resp.RequiresReplace = true
+			resp.Diagnostics.AddAttributeWarning(req.Path,
+				fmt.Sprintf("Renaming the %s replaces it", kind),
+				fmt.Sprintf("The name of a %s is its ID in the Baselime API, which cannot be renamed. "+
+					"Terraform will delete the %s %q and create %q in its place; "+
+					"resources referencing its id are updated to point to the new %s.\n\n"+
+					"Set create_before_destroy in the lifecycle block of the resource to create %q "+
+					"before the references to %q are removed.",
+					kind, kind, req.StateValue.ValueString(), req.PlanValue.ValueString(), kind,
+					req.PlanValue.ValueString(), req.StateValue.ValueString()),
+			)
+		},
+		description,
+		description,
+	)
+}
+
+// idFromName plans the ID of a resource as its name, which is known before the object is
+// created. References to the ID therefore show the new value in the plan when the
+// resource is created or replaced, rather than "known after apply".
+func idFromName() planmodifier.String {
+	return idFromNameModifier{}
+}
+
+type idFromNameModifier struct{}
+
+func (m idFromNameModifier) Description(ctx context.Context) string {
+	return "The ID is the same as the name."
+}
+
+func (m idFromNameModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m idFromNameModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	// the resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var name types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+	if resp.Diagnostics.HasError() || name.IsUnknown() {
+		return
+	}
+	resp.PlanValue = name
+}